@@ -0,0 +1,32 @@
+package beatport
+
+import (
+	"context"
+	"errors"
+)
+
+// Account is the Beatport account a token belongs to.
+type Account struct {
+	ID           int    `json:"id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	Subscription string `json:"subscription"`
+}
+
+// GetMyAccount returns the account the client is logged in to.
+func (c *Client) GetMyAccount() (*Account, error) {
+	return c.GetMyAccountCtx(context.Background())
+}
+
+// GetMyAccountCtx is like GetMyAccount but uses ctx for its requests.
+func (c *Client) GetMyAccountCtx(ctx context.Context) (*Account, error) {
+	var account Account
+	if err := c.getJSON(ctx, c.BaseURL+"/my/account/", "get account", &account); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			return nil, &AuthError{Op: "get account", StatusCode: statusErr.StatusCode, Body: statusErr.Body}
+		}
+		return nil, err
+	}
+	return &account, nil
+}
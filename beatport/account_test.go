@@ -0,0 +1,39 @@
+package beatport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMyAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/my/account/" || r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id": 42, "username": "dj", "email": "dj@example.com", "subscription": "bp_link_pro"}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	account, err := client.GetMyAccount()
+	if err != nil {
+		t.Fatalf("GetMyAccount failed: %v", err)
+	}
+	want := Account{ID: 42, Username: "dj", Email: "dj@example.com", Subscription: "bp_link_pro"}
+	if *account != want {
+		t.Errorf("GetMyAccount = %+v, want %+v", *account, want)
+	}
+
+	client.Token = &OAuthToken{AccessToken: "other-token"}
+	var authErr *AuthError
+	if _, err := client.GetMyAccount(); !errors.As(err, &authErr) || authErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected an AuthError with status 401, got %v", err)
+	}
+}
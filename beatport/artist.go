@@ -0,0 +1,42 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// artistTracksPageSize is how many of an artist's tracks GetArtistTracks
+// fetches, newest first.
+const artistTracksPageSize = 100
+
+// GetArtist returns the details of an artist, including the image and bio
+// that are left out when the artist is embedded in a track.
+func (c *Client) GetArtist(id int) (*Artist, error) {
+	return c.GetArtistCtx(context.Background(), id)
+}
+
+// GetArtistCtx is like GetArtist but uses ctx for its requests.
+func (c *Client) GetArtistCtx(ctx context.Context, id int) (*Artist, error) {
+	url := fmt.Sprintf("%s/catalog/artists/%d/", c.BaseURL, id)
+	var artist Artist
+	if err := c.getJSON(ctx, url, fmt.Sprintf("get artist %d", id), &artist); err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// GetArtistTracks returns the most recently released tracks of an artist.
+// Like GetTracksPaginated, it returns the tracks fetched before a failing
+// page along with the error.
+func (c *Client) GetArtistTracks(id int) ([]Track, error) {
+	return c.GetArtistTracksCtx(context.Background(), id)
+}
+
+// GetArtistTracksCtx is like GetArtistTracks but uses ctx for its requests.
+func (c *Client) GetArtistTracksCtx(ctx context.Context, id int) (_ []Track, err error) {
+	defer c.observeFetch("artist_tracks", time.Now(), &err)
+
+	startURL := fmt.Sprintf("%s/catalog/artists/%d/tracks/?per_page=%d&order_by=-publish_date", c.BaseURL, id, c.perPage(artistTracksPageSize))
+	return c.fetchTrackPages(ctx, startURL, artistTracksPageSize)
+}
@@ -0,0 +1,54 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetArtist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/artists/42/" {
+			t.Errorf("Expected path /catalog/artists/42/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 42, "name": "Nova Static", "slug": "nova-static", "bio": "Producer from Rotterdam.", "image": {"id": 7, "uri": "https://geo-media.beatport.com/image_size/590x404/7.jpg"}}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	artist, err := client.GetArtist(42)
+	if err != nil {
+		t.Fatalf("GetArtist failed: %v", err)
+	}
+	if artist.Name != "Nova Static" || artist.Bio == "" || artist.Image == nil || artist.Image.URI == "" {
+		t.Errorf("Unexpected artist: %+v", artist)
+	}
+}
+
+func TestGetArtistTracks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/artists/42/tracks/" {
+			t.Errorf("Expected path /catalog/artists/42/tracks/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"count": 2, "results": [{"id": 1, "name": "Newest"}, {"id": 2, "name": "Older"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.GetArtistTracks(42)
+	if err != nil {
+		t.Fatalf("GetArtistTracks failed: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Name != "Newest" {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+}
@@ -0,0 +1,49 @@
+package beatport
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token.json")
+	if err := os.WriteFile(path, []byte(`{"access_token": "old"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write that fails halfway must leave the old file intact
+	errWrite := errors.New("killed mid-write")
+	err := WriteFileAtomic(path, 0o600, func(w io.Writer) error {
+		if _, err := io.WriteString(w, `{"access_tok`); err != nil {
+			return err
+		}
+		return errWrite
+	})
+	if !errors.Is(err, errWrite) {
+		t.Fatalf("Expected the write error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"access_token": "old"}` {
+		t.Errorf("Old file was changed to %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected the temporary file to be removed, got %v", entries)
+	}
+
+	err = WriteFileAtomic(path, 0o600, func(w io.Writer) error {
+		_, err := io.WriteString(w, `{"access_token": "new"}`)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"access_token": "new"}` {
+		t.Errorf("Expected the new contents, got %q", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600, got %v (%v)", info.Mode(), err)
+	}
+}
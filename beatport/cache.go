@@ -0,0 +1,197 @@
+package beatport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clientIDCache is the on-disk form of a scraped API client ID.
+type clientIDCache struct {
+	ClientID  string    `json:"client_id"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// loadCachedClientID returns the cached client ID if it is younger than
+// ClientIDTTL. A TTL of zero or less disables the cache.
+func (c *Client) loadCachedClientID() (string, bool) {
+	if c.ClientIDTTL <= 0 {
+		return "", false
+	}
+
+	file, err := os.Open(c.ClientIDPath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	var cache clientIDCache
+	if err := json.NewDecoder(file).Decode(&cache); err != nil {
+		return "", false
+	}
+	if cache.ClientID == "" || time.Since(cache.FetchedAt) > c.ClientIDTTL {
+		return "", false
+	}
+	return cache.ClientID, true
+}
+
+func (c *Client) saveCachedClientID() error {
+	if c.ClientIDTTL <= 0 || c.Ephemeral {
+		return nil
+	}
+
+	return WriteFileAtomic(c.ClientIDPath, 0o644, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(clientIDCache{
+			ClientID:  c.ClientID,
+			FetchedAt: time.Now(),
+		})
+	})
+}
+
+// genresCache is the on-disk form of the genre list.
+type genresCache struct {
+	Genres    []Genre   `json:"genres"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// GetGenresCached returns the genre list from GenresPath if it is younger than
+// GenresTTL, and otherwise fetches it and refreshes the cache.
+func (c *Client) GetGenresCached() ([]Genre, error) {
+	return c.GetGenresCachedCtx(context.Background())
+}
+
+// GetGenresCachedCtx is like GetGenresCached but uses ctx for its requests.
+func (c *Client) GetGenresCachedCtx(ctx context.Context) ([]Genre, error) {
+	if genres, ok := c.loadCachedGenres(); ok {
+		return genres, nil
+	}
+
+	genres, err := c.GetGenresCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Failing to cache the list only means it is fetched again next time
+	if err := c.saveCachedGenres(genres); err != nil {
+		c.logger().Debug("failed to cache genres", "path", c.GenresPath, "error", err)
+	}
+	return genres, nil
+}
+
+// InvalidateGenreCache removes the cached genre list so the next lookup
+// fetches it again.
+func (c *Client) InvalidateGenreCache() error {
+	c.memGenres = nil
+	if err := os.Remove(c.GenresPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ResolveGenre looks up a genre by name or slug (case-insensitively) or by
+// numeric ID, so "Tech House", "tech-house" and "11" all resolve to the same
+// genre. The cached genre list is used when possible. It returns an error
+// matching ErrGenreNotFound if no genre matches.
+func (c *Client) ResolveGenre(query string) (*Genre, error) {
+	return c.ResolveGenreCtx(context.Background(), query)
+}
+
+// ResolveGenreCtx is like ResolveGenre but uses ctx for its requests.
+func (c *Client) ResolveGenreCtx(ctx context.Context, query string) (*Genre, error) {
+	genres, err := c.GetGenresCachedCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	id, idErr := strconv.Atoi(query)
+	for _, g := range genres {
+		if strings.EqualFold(g.Name, query) || strings.EqualFold(g.Slug, query) || (idErr == nil && g.ID == id) {
+			return &g, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrGenreNotFound, query)
+}
+
+// findGenre resolves query like ResolveGenre and, if no genre matches it
+// exactly, falls back to the genres whose name or slug contains it, so
+// "melodic" finds "Melodic House & Techno". It returns an error matching
+// ErrGenreAmbiguous if that leaves more than one genre.
+func (c *Client) findGenre(ctx context.Context, query string) (*Genre, error) {
+	genre, err := c.ResolveGenreCtx(ctx, query)
+	if !errors.Is(err, ErrGenreNotFound) {
+		return genre, err
+	}
+
+	genres, err := c.GetGenresCachedCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(strings.TrimSpace(query))
+	var matches []Genre
+	for _, g := range genres {
+		if needle != "" && (strings.Contains(strings.ToLower(g.Name), needle) || strings.Contains(strings.ToLower(g.Slug), needle)) {
+			matches = append(matches, g)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrGenreNotFound, query)
+	case 1:
+		return &matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, g := range matches {
+		names[i] = g.Name
+	}
+	return nil, fmt.Errorf("%w: %q matches %s", ErrGenreAmbiguous, query, strings.Join(names, ", "))
+}
+
+func (c *Client) loadCachedGenres() ([]Genre, bool) {
+	if c.GenresTTL <= 0 {
+		return nil, false
+	}
+	if c.Ephemeral {
+		if c.memGenres == nil || time.Since(c.memGenres.FetchedAt) > c.GenresTTL {
+			return nil, false
+		}
+		return c.memGenres.Genres, true
+	}
+
+	file, err := os.Open(c.GenresPath)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var cache genresCache
+	if err := json.NewDecoder(file).Decode(&cache); err != nil {
+		return nil, false
+	}
+	if len(cache.Genres) == 0 || time.Since(cache.FetchedAt) > c.GenresTTL {
+		return nil, false
+	}
+	return cache.Genres, true
+}
+
+func (c *Client) saveCachedGenres(genres []Genre) error {
+	if c.GenresTTL <= 0 {
+		return nil
+	}
+	if c.Ephemeral {
+		c.memGenres = &genresCache{Genres: genres, FetchedAt: time.Now()}
+		return nil
+	}
+
+	return WriteFileAtomic(c.GenresPath, 0o644, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(genresCache{
+			Genres:    genres,
+			FetchedAt: time.Now(),
+		})
+	})
+}
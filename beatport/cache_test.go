@@ -0,0 +1,158 @@
+package beatport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveGenreUsesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"results": [{"id": 6, "name": "Techno (Peak Time / Driving)", "slug": "techno-peak-time-driving"}, {"id": 5, "name": "House", "slug": "house"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.GenresPath = filepath.Join(t.TempDir(), GenresFile)
+
+	genre, err := client.ResolveGenre("house")
+	if err != nil {
+		t.Fatalf("ResolveGenre failed: %v", err)
+	}
+	if genre.ID != 5 {
+		t.Errorf("Expected genre 5, got %+v", genre)
+	}
+
+	if _, err := client.ResolveGenre("Trance"); !errors.Is(err, ErrGenreNotFound) {
+		t.Errorf("Expected ErrGenreNotFound, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the genre list to be fetched once, got %d requests", requests)
+	}
+
+	if err := client.InvalidateGenreCache(); err != nil {
+		t.Fatalf("InvalidateGenreCache failed: %v", err)
+	}
+	if _, err := client.ResolveGenre("House"); err != nil {
+		t.Fatalf("ResolveGenre after invalidation failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the genre list to be fetched again, got %d requests", requests)
+	}
+}
+
+func TestResolveGenreBySlugOrID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results": [{"id": 5, "name": "House", "slug": "house"}, {"id": 11, "name": "Tech House", "slug": "tech-house"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.GenresPath = filepath.Join(t.TempDir(), GenresFile)
+
+	for _, query := range []string{"Tech House", "tech-house", "TECH-HOUSE", "11", " 11 "} {
+		genre, err := client.ResolveGenre(query)
+		if err != nil {
+			t.Errorf("ResolveGenre(%q) failed: %v", query, err)
+			continue
+		}
+		if genre.ID != 11 {
+			t.Errorf("ResolveGenre(%q) = %+v, want genre 11", query, genre)
+		}
+	}
+	if _, err := client.ResolveGenre("12"); !errors.Is(err, ErrGenreNotFound) {
+		t.Errorf("Expected ErrGenreNotFound for an unknown ID, got %v", err)
+	}
+}
+
+func TestGetTop100ByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/catalog/genres/":
+			fmt.Fprint(w, `{"results": [{"id": 5, "name": "House", "slug": "house"}, {"id": 11, "name": "Tech House", "slug": "tech-house"}, {"id": 90, "name": "Melodic House & Techno", "slug": "melodic-house-techno"}]}`)
+		case "/catalog/genres/90/top/100":
+			fmt.Fprint(w, `{"results": [{"id": 1, "name": "Deep Waters"}]}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.GenresPath = filepath.Join(t.TempDir(), GenresFile)
+
+	tracks, err := client.GetTop100ByName("melodic")
+	if err != nil {
+		t.Fatalf("GetTop100ByName failed: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Deep Waters" {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+
+	if _, err := client.GetTop100ByName("hous"); !errors.Is(err, ErrGenreAmbiguous) {
+		t.Errorf("Expected ErrGenreAmbiguous, got %v", err)
+	}
+	if _, err := client.GetTop100ByName("Trance"); !errors.Is(err, ErrGenreNotFound) {
+		t.Errorf("Expected ErrGenreNotFound, got %v", err)
+	}
+}
+
+func TestEphemeralClientWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/o/token/":
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "session"})
+			fmt.Fprint(w, `{"access_token": "test-token", "refresh_token": "test-refresh", "expires_in": 3600}`)
+		case "/catalog/genres/":
+			requests++
+			fmt.Fprint(w, `{"results": [{"id": 5, "name": "House", "slug": "house"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Ephemeral = true
+
+	if err := client.GetToken("test-code"); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	for range 2 {
+		if _, err := client.ResolveGenre("house"); err != nil {
+			t.Fatalf("ResolveGenre failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the genre list to be cached in memory, got %d requests", requests)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("Ephemeral client wrote %s", entry.Name())
+	}
+}
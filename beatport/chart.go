@@ -0,0 +1,38 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetCharts returns the PageSize most recently published DJ charts.
+func (c *Client) GetCharts() ([]Chart, error) {
+	return c.GetChartsCtx(context.Background())
+}
+
+// GetChartsCtx is like GetCharts but uses ctx for its requests.
+func (c *Client) GetChartsCtx(ctx context.Context) ([]Chart, error) {
+	pageURL := fmt.Sprintf("%s/catalog/charts/?per_page=%d", c.BaseURL, c.perPage(0))
+	var chartResp ChartResponse
+	if err := c.getJSON(ctx, pageURL, "get charts", &chartResp); err != nil {
+		return nil, err
+	}
+	return chartResp.Results, nil
+}
+
+// GetChartTracks returns the tracklist of a DJ chart, in chart order. Like
+// GetTracksPaginated, it returns the tracks fetched before a failing page
+// along with the error.
+func (c *Client) GetChartTracks(chartID int) ([]Track, error) {
+	return c.GetChartTracksCtx(context.Background(), chartID)
+}
+
+// GetChartTracksCtx is like GetChartTracks but uses ctx for its requests.
+func (c *Client) GetChartTracksCtx(ctx context.Context, chartID int) (_ []Track, err error) {
+	defer c.observeFetch("chart_tracks", time.Now(), &err)
+
+	startURL := fmt.Sprintf("%s/catalog/charts/%d/tracks/?per_page=%d", c.BaseURL, chartID, c.perPage(0))
+	tracks, err := c.fetchTrackPages(ctx, startURL, 0)
+	return rankTracks(tracks), err
+}
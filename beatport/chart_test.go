@@ -0,0 +1,43 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCharts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/catalog/charts/":
+			fmt.Fprint(w, `{"count": 1, "results": [{"id": 77, "name": "Summer Selects", "artist": {"id": 1001, "name": "Example Artist"}, "image": {"id": 3, "uri": "https://geo-media.beatport.com/image_size/1400x1400/3.jpg"}}]}`)
+		case "/catalog/charts/77/tracks/":
+			fmt.Fprint(w, `{"count": 2, "results": [{"id": 1, "name": "First"}, {"id": 2, "name": "Second"}]}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	charts, err := client.GetCharts()
+	if err != nil {
+		t.Fatalf("GetCharts failed: %v", err)
+	}
+	if len(charts) != 1 || charts[0].Name != "Summer Selects" || charts[0].Artist == nil || charts[0].ImageURL == "" {
+		t.Fatalf("Unexpected charts: %+v", charts)
+	}
+
+	tracks, err := client.GetChartTracks(charts[0].ID)
+	if err != nil {
+		t.Fatalf("GetChartTracks failed: %v", err)
+	}
+	if len(tracks) != 2 || tracks[1].Name != "Second" || tracks[1].Rank != 2 {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+}
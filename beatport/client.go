@@ -2,70 +2,533 @@ package beatport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 const (
 	DefaultAPIBaseURL  = "https://api.beatport.com/v4"
 	DefaultAuthBaseURL = "https://api.beatport.com/v4/auth"
 	TokenFile          = "token.json"
-	MaxRetries         = 3
+	ClientIDFile       = "clientid.json"
+	GenresFile         = "genres.json"
+	CookiesFile        = "cookies.json"
+
+	// EnvAPIBaseURL and EnvAuthBaseURL name the environment variables that
+	// override DefaultAPIBaseURL and DefaultAuthBaseURL in new clients, e.g.
+	// to use a staging API, a local mock or a proxy.
+	EnvAPIBaseURL  = "BEATPORT_API_BASE"
+	EnvAuthBaseURL = "BEATPORT_AUTH_BASE"
+
+	// DefaultMaxRetries is how many times a failed request is retried by
+	// default.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBaseDelay is the default base of the exponential retry
+	// backoff: the nth retry waits RetryBaseDelay * 2^n.
+	DefaultRetryBaseDelay = time.Second
+
+	// Names of the genre charts.
+	ChartTop100  = "top-100"
+	ChartHype100 = "hype-100"
+
+	// DefaultClientIDTTL is how long a scraped client ID is reused before the
+	// docs page is scraped again.
+	DefaultClientIDTTL = 24 * time.Hour
+
+	// DefaultGenresTTL is how long the cached genre list is reused before it
+	// is fetched again.
+	DefaultGenresTTL = 7 * 24 * time.Hour
+
+	// DefaultUserAgent is the User-Agent sent by default. Beatport's edge is
+	// known to challenge Go's default User-Agent, so it mimics a browser.
+	DefaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+	// DefaultTimeout is the time limit of a single HTTP request made by a
+	// client created with NewClient. Retries get a time limit of their own.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultPageSize is the default number of results requested per page.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page size the API accepts.
+	MaxPageSize = 100
+
+	// DefaultRateLimit is the default maximum number of requests per second.
+	DefaultRateLimit = 5
+
+	// tokenExpiryMargin is how long before its expiry a token is considered
+	// stale, so it isn't used for a request that could outlive it.
+	tokenExpiryMargin = 60 * time.Second
+
+	// maxRetryAfter caps how long a Retry-After header can make us wait.
+	maxRetryAfter = time.Minute
 )
 
+var discardLogger = slog.New(slog.DiscardHandler)
+
 type Client struct {
 	HTTPClient *http.Client
 	Token      *OAuthToken
 	ClientID   string
 	BaseURL    string
 	AuthURL    string
+
+	// TokenPath and ClientIDPath are where the OAuth token and the scraped
+	// client ID are persisted. They default to TokenFile and ClientIDFile in
+	// the working directory.
+	TokenPath    string
+	ClientIDPath string
+
+	// MaxTokenAge, if positive, is how long after logging in a saved token
+	// is still loaded, however often it was refreshed. Older tokens are
+	// deleted, so the user has to log in again.
+	MaxTokenAge time.Duration
+
+	// Scopes are the OAuth scopes requested when authorizing. Without them
+	// the scopes of the saved token are requested again, and without a saved
+	// token Beatport grants its default scopes. A warning is logged when a
+	// requested scope isn't granted.
+	Scopes []string
+
+	// ClientIDTTL is how long the client ID cached at ClientIDPath is trusted.
+	// Zero disables the cache.
+	ClientIDTTL time.Duration
+
+	// GenresPath is where the genre list is cached, for at most GenresTTL.
+	// A TTL of zero disables the cache.
+	GenresPath string
+	GenresTTL  time.Duration
+
+	// CookiesPath is where SaveCookies persists the login session.
+	CookiesPath string
+
+	// RateLimit is the maximum number of requests per second. Zero disables
+	// rate limiting.
+	RateLimit float64
+
+	// MaxRetries is how many times a request is retried after a network
+	// error, a 5xx or a 429 response. RetryBaseDelay is the base of the
+	// exponential backoff between retries (2x, 4x, 8x, ...).
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+
+	// PageSize is the number of results requested per page, between 1 and
+	// MaxPageSize. Larger values are clamped to MaxPageSize and zero or less
+	// uses DefaultPageSize. Endpoints that are paginated are followed until
+	// they run out, the others return a single page.
+	PageSize int
+
+	// UserAgent is sent with every request that doesn't set its own.
+	UserAgent string
+
+	// ClientVersion is sent in the ClientVersionHeader of every request. It
+	// defaults to ClientName and Version, e.g. "beatport-top100/v1.0.0";
+	// empty leaves the header out.
+	ClientVersion string
+
+	// Currency is the ISO 4217 code (e.g. "EUR") that catalog prices are
+	// requested in. Empty uses the currency of the account's region.
+	Currency string
+
+	// Offline serves every request from built-in fixtures (a small genre
+	// list, Top 100 and Top 100 releases) instead of the network, and accepts
+	// any credentials. Token and cache files are still written to their
+	// configured paths.
+	Offline bool
+
+	// Progress, if set, is called as fetches of charts spanning several
+	// pages, and multi-genre fetches, progress, e.g. to show a progress bar.
+	// Calls are never concurrent.
+	Progress ProgressFunc
+
+	// Logger receives debug logs about every request, and warnings such as
+	// a requested scope that wasn't granted. It defaults to a logger that
+	// discards everything.
+	Logger *slog.Logger
+
+	// Metrics receives measurements of requests, retries, authentication
+	// failures and fetches. It defaults to NopMetrics.
+	Metrics Metrics
+
+	// Tracer, if set, records an OpenTelemetry span for every HTTP attempt,
+	// as a child of the span in the context passed to the ...Ctx methods.
+	Tracer trace.Tracer
+
+	// Ephemeral keeps the token, session cookies and caches in memory only:
+	// nothing is written to TokenPath, ClientIDPath, GenresPath or
+	// CookiesPath. Files that already exist there are still read, but never
+	// changed or removed.
+	Ephemeral bool
+
+	// DumpDir, if set, receives a file for every HTTP attempt with the
+	// request and response, headers and bodies, for debugging. Passwords,
+	// tokens, authorization codes and cookies are redacted.
+	DumpDir string
+
+	tokenMu   sync.RWMutex
+	limiterMu sync.Mutex
+	limiter   *rate.Limiter
+
+	// codeVerifier is the PKCE code verifier of the last Authorize, sent
+	// along with the authorization code by GetToken.
+	codeVerifier string
+
+	// cookiesDirty is set when a response changed the session cookies since
+	// they were last saved.
+	cookiesDirty atomic.Bool
+
+	// memGenres is the genre cache of an Ephemeral client.
+	memGenres *genresCache
+
+	// dumpSeq numbers the files written to DumpDir.
+	dumpSeq atomic.Uint64
 }
 
 func NewClient() (*Client, error) {
-	jar, err := cookiejar.New(nil)
+	return NewClientWithTimeout(DefaultTimeout)
+}
+
+// NewClientWithTimeout creates a client whose requests time out after
+// timeout. A timeout of zero means no timeout. Requests go through the proxy
+// set in the environment, see ProxyTransport.
+func NewClientWithTimeout(timeout time.Duration) (*Client, error) {
+	transport, err := ProxyTransport("")
 	if err != nil {
 		return nil, err
 	}
+	return NewClientWithHTTPClient(&http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	})
+}
+
+// NewClientWithHTTPClient creates a client that sends its requests through hc,
+// e.g. to route traffic via a proxy (see ProxyTransport) or use a custom
+// transport. The API and auth base URLs can be overridden with the
+// EnvAPIBaseURL and EnvAuthBaseURL environment variables. The login session
+// relies on cookies, so a cookie jar is added if hc doesn't have one.
+func NewClientWithHTTPClient(hc *http.Client) (*Client, error) {
+	if hc == nil {
+		return nil, fmt.Errorf("http client is nil")
+	}
+	if hc.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		hc.Jar = jar
+	}
 	return &Client{
-		HTTPClient: &http.Client{
-			Jar:     jar,
-			Timeout: 30 * time.Second,
-		},
-		BaseURL: DefaultAPIBaseURL,
-		AuthURL: DefaultAuthBaseURL,
+		HTTPClient:     hc,
+		BaseURL:        baseURLFromEnv(EnvAPIBaseURL, DefaultAPIBaseURL),
+		AuthURL:        baseURLFromEnv(EnvAuthBaseURL, DefaultAuthBaseURL),
+		TokenPath:      TokenFile,
+		ClientIDPath:   ClientIDFile,
+		ClientIDTTL:    DefaultClientIDTTL,
+		GenresPath:     GenresFile,
+		GenresTTL:      DefaultGenresTTL,
+		CookiesPath:    CookiesFile,
+		UserAgent:      DefaultUserAgent,
+		ClientVersion:  ClientName + "/" + Version(),
+		RateLimit:      DefaultRateLimit,
+		PageSize:       DefaultPageSize,
+		MaxRetries:     DefaultMaxRetries,
+		RetryBaseDelay: DefaultRetryBaseDelay,
+		Logger:         discardLogger,
+		Metrics:        NopMetrics{},
 	}, nil
 }
 
-// doRequest performs an HTTP request with exponential backoff retry
+// baseURLFromEnv returns the base URL set in the environment variable key,
+// without a trailing slash, or def if it isn't set.
+func baseURLFromEnv(key, def string) string {
+	if base := strings.TrimRight(os.Getenv(key), "/"); base != "" {
+		return base
+	}
+	return def
+}
+
+// Close saves the session cookies if they changed since they were last saved
+// and closes the idle connections of the HTTP client. The client should not
+// be used afterwards.
+func (c *Client) Close() error {
+	var err error
+	if c.cookiesDirty.Load() && c.CookiesPath != "" {
+		err = c.SaveCookies()
+	}
+	c.HTTPClient.CloseIdleConnections()
+	return err
+}
+
+// doRequest performs an HTTP request with exponential backoff retry.
+// Requests are throttled to RateLimit, a Retry-After header on a 429 or 5xx
+// response overrides the backoff, and the backoff is aborted when the
+// request's context is done.
 func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	return c.doRequestWith(c.HTTPClient, req)
+}
+
+// doRequestWith is like doRequest but sends the request with hc instead of
+// HTTPClient.
+func (c *Client) doRequestWith(hc *http.Client, req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
+	var delay time.Duration
+	maxRetries := max(c.MaxRetries, 0)
+	if c.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.ClientVersion != "" && req.Header.Get(ClientVersionHeader) == "" {
+		req.Header.Set(ClientVersionHeader, c.ClientVersion)
+	}
+	endpoint := endpointLabel(req.URL.Path)
 
-	for i := 0; i <= MaxRetries; i++ {
+	for i := 0; i <= maxRetries; i++ {
 		if i > 0 {
-			time.Sleep(time.Duration(1<<uint(i)) * time.Second) // 2s, 4s, 8s
+			if err := sleepCtx(req.Context(), delay); err != nil {
+				return nil, err
+			}
+			// The previous attempt consumed the body
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+		if err := c.waitRateLimit(req.Context()); err != nil {
+			return nil, err
+		}
+
+		var dumpBody []byte
+		if c.DumpDir != "" {
+			dumpBody = dumpRequestBody(req)
 		}
-		resp, err = c.HTTPClient.Do(req)
-		if err == nil && resp.StatusCode < 500 {
+
+		start := time.Now()
+		attemptReq, span := c.startAttemptSpan(req, i)
+		if c.Offline {
+			resp, err = c.serveOffline(attemptReq)
+		} else {
+			resp, err = hc.Do(attemptReq)
+		}
+		endAttemptSpan(span, resp, err)
+		if c.DumpDir != "" {
+			c.dumpAttempt(attemptReq, dumpBody, resp, err)
+		}
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.metrics().ObserveRequest(req.Method, endpoint, status, time.Since(start))
+		if err == nil && len(resp.Header.Values("Set-Cookie")) > 0 {
+			c.cookiesDirty.Store(true)
+		}
+		if err != nil {
+			c.logger().Debug("request failed", "method", req.Method, "url", req.URL.Redacted(), "attempt", i+1, "error", err)
+		} else {
+			c.logger().Debug("request", "method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "attempt", i+1)
+		}
+
+		if err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
 			return resp, nil
 		}
+		if i == maxRetries {
+			break // Leave the last response for the caller to inspect
+		}
+
+		delay = c.RetryBaseDelay << uint(i+1) // 2s, 4s, 8s by default
 		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = min(retryAfter, maxRetryAfter)
+			}
 			_ = resp.Body.Close()
 		}
+		c.logger().Debug("retrying request", "method", req.Method, "url", req.URL.Redacted(), "delay", delay)
+		c.metrics().IncRetry(req.Method, endpoint)
+	}
+
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s %s failed after %d attempts", ErrRateLimited, req.Method, req.URL.Path, maxRetries+1)
 	}
 	return resp, err
 }
 
+// perPage returns the page size to request: PageSize within the range the
+// API accepts, and no more than limit if it is positive.
+func (c *Client) perPage(limit int) int {
+	size := c.PageSize
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	size = min(size, MaxPageSize)
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	return size
+}
+
+// logger returns the configured logger, discarding output if there is none.
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return discardLogger
+	}
+	return c.Logger
+}
+
+// waitRateLimit blocks until the rate limiter allows another request.
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.RateLimit <= 0 {
+		return nil
+	}
+
+	c.limiterMu.Lock()
+	if c.limiter == nil || c.limiter.Limit() != rate.Limit(c.RateLimit) {
+		c.limiter = rate.NewLimiter(rate.Limit(c.RateLimit), 1)
+	}
+	limiter := c.limiter
+	c.limiterMu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepCtx waits for d, returning early with the context's error if ctx is
+// done first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// doAuthRequest performs an authenticated request. If the API responds with
+// 401 Unauthorized the access token is refreshed and the request retried once.
+func (c *Client) doAuthRequest(req *http.Request) (*http.Response, error) {
+	token := c.currentToken()
+	if token == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	if c.Currency != "" && strings.Contains(req.URL.Path, "/catalog/") {
+		q := req.URL.Query()
+		q.Set("price_code", c.Currency)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || token.RefreshToken == "" {
+		return resp, err
+	}
+	_ = resp.Body.Close()
+
+	if err := c.refreshStaleToken(req.Context(), token); err != nil {
+		c.observeAuthFailure("token refresh", err)
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", "Bearer "+c.currentToken().AccessToken)
+	return c.doRequest(retry)
+}
+
+// Get performs an authenticated GET request for an API endpoint that has no
+// method of its own and decodes the JSON response into out, like
+// json.Unmarshal. path is relative to BaseURL and may include a query, e.g.
+// "/catalog/labels/1/tracks/?per_page=10". Like the other methods, it renews
+// an expired token and retries failed requests. A response other than
+// 200 OK is returned as a *StatusError.
+func (c *Client) Get(path string, out any) error {
+	return c.GetCtx(context.Background(), path, out)
+}
+
+// GetCtx is like Get but uses ctx for its requests.
+func (c *Client) GetCtx(ctx context.Context, path string, out any) error {
+	return c.getJSON(ctx, c.BaseURL+"/"+strings.TrimPrefix(path, "/"), "get "+path, out)
+}
+
+// getJSON performs an authenticated GET request and decodes the JSON response
+// into out. op describes the request in the error for a response other than
+// 200 OK, e.g. "get genres".
+func (c *Client) getJSON(ctx context.Context, url, op string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doAuthRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{Op: op, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) currentToken() *OAuthToken {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.Token
+}
+
+// refreshStaleToken refreshes the token unless a concurrent request already
+// replaced the stale one, so parallel 401s only trigger a single refresh.
+func (c *Client) refreshStaleToken(ctx context.Context, stale *OAuthToken) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.Token != nil && c.Token != stale {
+		return nil
+	}
+	return c.RefreshTokenCtx(ctx)
+}
+
+// LoadToken reads the token saved at TokenPath. A token issued longer than
+// MaxTokenAge ago is deleted instead, and an error matching ErrTokenTooOld
+// is returned.
 func (c *Client) LoadToken() error {
-	file, err := os.Open(TokenFile)
+	file, err := os.Open(c.TokenPath)
 	if err != nil {
 		return err
 	}
@@ -75,25 +538,110 @@ func (c *Client) LoadToken() error {
 	if err := json.NewDecoder(file).Decode(&token); err != nil {
 		return err
 	}
+	// A token from before IssuedAt was recorded is of unknown age
+	if c.MaxTokenAge > 0 && (token.IssuedAt.IsZero() || time.Since(token.IssuedAt) > c.MaxTokenAge) {
+		if !c.Ephemeral {
+			_ = os.Remove(c.TokenPath)
+		}
+		return &AuthError{Op: "load token", Err: ErrTokenTooOld}
+	}
 	c.Token = &token
+	if c.ClientID == "" {
+		c.ClientID = token.ClientID
+	}
 	return nil
 }
 
+// TokenValid reports whether the client holds an access token that is not
+// within a minute of expiring. Tokens without a known expiry are assumed valid.
+func (c *Client) TokenValid() bool {
+	if c.Token == nil || c.Token.AccessToken == "" {
+		return false
+	}
+	if c.Token.ExpiresAt.IsZero() {
+		return true
+	}
+	return time.Until(c.Token.ExpiresAt) > tokenExpiryMargin
+}
+
+// setToken stores a freshly issued token, stamping its expiry time, the
+// client ID it was issued to and, unless it is a refreshed one, its issue
+// time.
+func (c *Client) setToken(token *OAuthToken) {
+	if token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	if token.IssuedAt.IsZero() {
+		token.IssuedAt = time.Now()
+	}
+	token.ClientID = c.ClientID
+	c.Token = token
+}
+
+// SaveToken writes the token to TokenPath. It does nothing if the client is
+// Ephemeral.
 func (c *Client) SaveToken() error {
 	if c.Token == nil {
 		return fmt.Errorf("no token to save")
 	}
-	file, err := os.Create(TokenFile)
-	if err != nil {
-		return err
+	if c.Ephemeral {
+		return nil
 	}
-	defer file.Close()
+	return WriteFileAtomic(c.TokenPath, 0o600, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(c.Token)
+	})
+}
+
+var (
+	// reScriptSrc matches the script bundles linked from the docs page.
+	reScriptSrc = regexp.MustCompile(`src=["'](/static/btprt/[^"']+\.js)["']`)
+	// reClientID matches the client ID in a bundle, minified or not and with
+	// either quote style.
+	reClientID = regexp.MustCompile(`API_CLIENT_ID\s*:\s*["']([^"']+)["']`)
+)
 
-	return json.NewEncoder(file).Encode(c.Token)
+// challengeMarkers are strings found in Cloudflare's challenge and block
+// pages.
+var challengeMarkers = []string{
+	"cf-browser-verification",
+	"cf-challenge",
+	"cf_chl_",
+	"challenge-platform",
+	"<title>Just a moment...</title>",
+	"Attention Required! | Cloudflare",
 }
 
+// isChallenge reports whether resp, with the given body, is a Cloudflare
+// challenge page rather than the requested resource.
+func isChallenge(resp *http.Response, body []byte) bool {
+	if resp.Header.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(body, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchClientID scrapes the API client ID from the Beatport docs page. A
+// previously scraped ID younger than ClientIDTTL is reused from ClientIDPath.
 func (c *Client) FetchClientID() error {
-	req, err := http.NewRequest("GET", c.BaseURL+"/docs/", nil)
+	return c.FetchClientIDCtx(context.Background())
+}
+
+// FetchClientIDCtx is like FetchClientID but uses ctx for its requests.
+func (c *Client) FetchClientIDCtx(ctx context.Context) error {
+	if clientID, ok := c.loadCachedClientID(); ok {
+		c.ClientID = clientID
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/docs/", nil)
 	if err != nil {
 		return err
 	}
@@ -108,10 +656,12 @@ func (c *Client) FetchClientID() error {
 	if err != nil {
 		return err
 	}
+	if isChallenge(resp, body) {
+		return fmt.Errorf("fetching client ID (status %d): %w", resp.StatusCode, ErrChallenge)
+	}
 
-	// Find script src
-	reScript := regexp.MustCompile(`src="(/static/btprt/.*\.js)"`)
-	matches := reScript.FindAllStringSubmatch(string(body), -1)
+	// Beatport ships several hashed bundles; any one of them may hold the ID
+	matches := reScriptSrc.FindAllStringSubmatch(string(body), -1)
 
 	for _, match := range matches {
 		// Handle relative URLs correctly if we are mocking
@@ -135,23 +685,26 @@ func (c *Client) FetchClientID() error {
 			}
 		}
 
-		reqScript, _ := http.NewRequest("GET", scriptURL, nil)
+		reqScript, _ := http.NewRequestWithContext(ctx, "GET", scriptURL, nil)
 		scriptResp, err := c.doRequest(reqScript)
 		if err != nil {
 			continue
 		}
-		defer scriptResp.Body.Close()
-
 		jsBody, err := io.ReadAll(scriptResp.Body)
+		scriptResp.Body.Close()
 		if err != nil {
 			continue
 		}
+		if isChallenge(scriptResp, jsBody) {
+			return fmt.Errorf("fetching client ID (status %d): %w", scriptResp.StatusCode, ErrChallenge)
+		}
 
-		// Find client_id
-		reClientID := regexp.MustCompile(`API_CLIENT_ID: \'(.*)\'`)
-		clientMatches := reClientID.FindAllStringSubmatch(string(jsBody), -1)
-		if len(clientMatches) > 0 {
-			c.ClientID = clientMatches[0][1]
+		if clientMatch := reClientID.FindSubmatch(jsBody); clientMatch != nil {
+			c.ClientID = string(clientMatch[1])
+			// Failing to cache the ID only means it is scraped again next time
+			if err := c.saveCachedClientID(); err != nil {
+				c.logger().Debug("failed to cache client ID", "path", c.ClientIDPath, "error", err)
+			}
 			return nil
 		}
 	}
@@ -159,12 +712,28 @@ func (c *Client) FetchClientID() error {
 	return fmt.Errorf("could not fetch API_CLIENT_ID")
 }
 
+// Login logs in to Beatport. A saved token that is still valid, or can be
+// refreshed, is used instead, in which case username and password may be
+// empty.
 func (c *Client) Login(username, password string) error {
-	// Try loading token first
+	return c.LoginCtx(context.Background(), username, password)
+}
+
+// LoginCtx is like Login but uses ctx for its requests.
+func (c *Client) LoginCtx(ctx context.Context, username, password string) error {
+	// Try loading token first, refreshing it if it is about to expire or
+	// the server no longer accepts it
 	if err := c.LoadToken(); err == nil {
-		// Validate token (optional, but good practice)
-		// For now, we assume it's valid or will fail later
-		return nil
+		if c.TokenValid() && c.ValidateTokenCtx(ctx) == nil {
+			return nil
+		}
+		if err := c.RefreshTokenCtx(ctx); err == nil {
+			return nil
+		}
+		c.Token = nil
+	}
+	if username == "" || password == "" {
+		return &AuthError{Op: "login", Err: ErrNoCredentials}
 	}
 
 	loginURL := c.AuthURL + "/login/"
@@ -177,7 +746,7 @@ func (c *Client) Login(username, password string) error {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", loginURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", loginURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
@@ -189,50 +758,187 @@ func (c *Client) Login(username, password string) error {
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
 	var res map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+	if err := json.Unmarshal(body, &res); err != nil {
+		return &AuthError{Op: "login", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if verificationRequired(res) {
+		return &AuthError{Op: "login", StatusCode: resp.StatusCode, Body: string(body), Err: ErrVerificationRequired}
+	}
+	if _, ok := res["username"]; !ok {
+		return &AuthError{Op: "login", StatusCode: resp.StatusCode, Body: loginErrorMessage(res, body), Err: ErrInvalidCredentials}
+	}
+
+	return nil
+}
+
+// verificationRequired reports whether a login response asks for the code
+// that was emailed to the user.
+func verificationRequired(res map[string]interface{}) bool {
+	if required, _ := res["verification_required"].(bool); required {
+		return true
+	}
+	detail, _ := res["detail"].(string)
+	return strings.Contains(strings.ToLower(detail), "verification")
+}
+
+// loginErrorMessage returns the reason a login response gives for rejecting
+// the credentials, or the whole body if it gives none. Beatport reports it
+// under different keys, sometimes with a 200 status.
+func loginErrorMessage(res map[string]interface{}, body []byte) string {
+	for _, key := range []string{"error_description", "detail", "message", "non_field_errors", "error"} {
+		switch v := res[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case []interface{}:
+			msgs := make([]string, 0, len(v))
+			for _, msg := range v {
+				if msg, ok := msg.(string); ok {
+					msgs = append(msgs, msg)
+				}
+			}
+			if len(msgs) > 0 {
+				return strings.Join(msgs, "; ")
+			}
+		}
+	}
+	return string(body)
+}
+
+// SubmitVerificationCode completes a login that failed with
+// ErrVerificationRequired. The login session is kept in the client's cookie
+// jar, so it must be called on the same client.
+func (c *Client) SubmitVerificationCode(code string) error {
+	return c.SubmitVerificationCodeCtx(context.Background(), code)
+}
+
+// SubmitVerificationCodeCtx is like SubmitVerificationCode but uses ctx for
+// its requests.
+func (c *Client) SubmitVerificationCodeCtx(ctx context.Context, code string) error {
+	jsonData, err := json.Marshal(map[string]string{"code": code})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.AuthURL+"/login/verify/", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return err
 	}
 
+	var res map[string]interface{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return &AuthError{Op: "verification", StatusCode: resp.StatusCode, Body: string(body)}
+	}
 	if _, ok := res["username"]; !ok {
-		return fmt.Errorf("login failed: %v", res)
+		return &AuthError{Op: "verification", StatusCode: resp.StatusCode, Body: loginErrorMessage(res, body), Err: ErrInvalidCredentials}
 	}
+	return nil
+}
+
+// ValidateToken checks that the server accepts the current token by fetching
+// the account it belongs to. It returns an AuthError if it does not.
+func (c *Client) ValidateToken() error {
+	return c.ValidateTokenCtx(context.Background())
+}
 
+// ValidateTokenCtx is like ValidateToken but uses ctx for its requests.
+func (c *Client) ValidateTokenCtx(ctx context.Context) error {
+	token := c.currentToken()
+	if token == nil {
+		return &AuthError{Op: "validate token", Err: ErrNoToken}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/my/account/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &AuthError{Op: "validate token", StatusCode: resp.StatusCode, Body: string(body)}
+	}
 	return nil
 }
 
 func (c *Client) Authorize() (string, error) {
-	// If we already have a token, skip authorization
-	if c.Token != nil {
+	return c.AuthorizeCtx(context.Background())
+}
+
+// AuthorizeCtx is like Authorize but uses ctx for its requests.
+func (c *Client) AuthorizeCtx(ctx context.Context) (string, error) {
+	// If we already have a usable token, skip authorization
+	if c.TokenValid() {
 		return "", nil
 	}
 
 	if c.ClientID == "" {
-		if err := c.FetchClientID(); err != nil {
+		if err := c.FetchClientIDCtx(ctx); err != nil {
 			return "", err
 		}
 	}
 
+	// PKCE binds the authorization code to this client: GetToken has to
+	// present the verifier the challenge was derived from
+	verifier, err := newCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+	c.codeVerifier = verifier
+
 	redirectURI := c.AuthURL + "/o/post-message/"
 	params := url.Values{}
 	params.Set("response_type", "code")
 	params.Set("client_id", c.ClientID)
 	params.Set("redirect_uri", redirectURI)
+	params.Set("code_challenge", codeChallenge(verifier))
+	params.Set("code_challenge_method", "S256")
+	if scopes := c.requestedScopes(); len(scopes) > 0 {
+		params.Set("scope", strings.Join(scopes, " "))
+	}
 
 	authURL := c.AuthURL + "/o/authorize/?" + params.Encode()
 
-	// We need to prevent redirects to capture the Location header
-	c.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+	// We need to prevent redirects to capture the Location header. A copy
+	// of the HTTP client does that, so a client passed to
+	// NewClientWithHTTPClient, possibly shared, keeps its redirect policy.
+	hc := *c.HTTPClient
+	hc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
-	defer func() { c.HTTPClient.CheckRedirect = nil }()
 
-	req, err := http.NewRequest("GET", authURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", authURL, nil)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWith(&hc, req)
 	if err != nil {
 		return "", err
 	}
@@ -259,7 +965,12 @@ func (c *Client) Authorize() (string, error) {
 }
 
 func (c *Client) GetToken(code string) error {
-	if c.Token != nil {
+	return c.GetTokenCtx(context.Background(), code)
+}
+
+// GetTokenCtx is like GetToken but uses ctx for its requests.
+func (c *Client) GetTokenCtx(ctx context.Context, code string) error {
+	if c.TokenValid() {
 		return nil
 	}
 
@@ -271,10 +982,14 @@ func (c *Client) GetToken(code string) error {
 	data.Set("grant_type", "authorization_code")
 	data.Set("redirect_uri", redirectURI)
 	data.Set("client_id", c.ClientID)
+	if c.codeVerifier != "" {
+		data.Set("code_verifier", c.codeVerifier)
+		c.codeVerifier = ""
+	}
 
 	// PostForm uses Client.PostForm which doesn't use our doRequest wrapper easily
 	// Let's construct a request
-	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -288,95 +1003,452 @@ func (c *Client) GetToken(code string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to get token: %s", string(body))
+		return &AuthError{Op: "token exchange", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var token OAuthToken
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
 		return err
 	}
+	for _, scope := range c.requestedScopes() {
+		if !token.HasScope(scope) {
+			c.logger().Warn("requested scope was not granted", "scope", scope, "granted", token.Scope)
+		}
+	}
 
-	c.Token = &token
+	c.setToken(&token)
 	return c.SaveToken()
 }
 
-func (c *Client) GetGenres() ([]Genre, error) {
-	url := c.BaseURL + "/catalog/genres/?per_page=100"
-	req, err := http.NewRequest("GET", url, nil)
+// requestedScopes returns the scopes to request when authorizing: Scopes, or
+// else those of the saved token, so a new token gets the same permissions.
+func (c *Client) requestedScopes() []string {
+	if len(c.Scopes) > 0 {
+		return c.Scopes
+	}
+	if c.Token != nil {
+		return strings.Fields(c.Token.Scope)
+	}
+	return nil
+}
+
+// RefreshToken exchanges the refresh token for a new access token and saves it.
+// It uses the client ID saved with the token, or the cached one, and only
+// scrapes a new client ID if neither is known.
+// If the refresh token is rejected, the saved token is removed and an error
+// matching ErrRefreshTokenExpired is returned.
+func (c *Client) RefreshToken() error {
+	return c.RefreshTokenCtx(context.Background())
+}
+
+// RefreshTokenCtx is like RefreshToken but uses ctx for its requests.
+func (c *Client) RefreshTokenCtx(ctx context.Context) error {
+	if c.Token == nil || c.Token.RefreshToken == "" {
+		return &AuthError{Op: "token refresh", Err: ErrRefreshTokenExpired}
+	}
+
+	// The refresh token belongs to the client it was issued to, so that ID
+	// is used when known. Scraping one is only the last resort.
+	if c.Token.ClientID != "" {
+		c.ClientID = c.Token.ClientID
+	} else if c.ClientID == "" {
+		if err := c.FetchClientIDCtx(ctx); err != nil {
+			return err
+		}
+	}
+
+	tokenURL := c.AuthURL + "/o/token/"
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", c.Token.RefreshToken)
+	data.Set("client_id", c.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return nil, err
+		return err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token.AccessToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := c.doRequest(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to get genres: %s", string(body))
+		authErr := &AuthError{Op: "token refresh", StatusCode: resp.StatusCode, Body: string(body)}
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+			c.Token = nil
+			if !c.Ephemeral {
+				_ = os.Remove(c.TokenPath)
+			}
+			authErr.Err = ErrRefreshTokenExpired
+		}
+		return authErr
+	}
+
+	var token OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return err
+	}
+	// Some servers don't rotate the refresh token; keep the old one then.
+	if token.RefreshToken == "" {
+		token.RefreshToken = c.Token.RefreshToken
 	}
+	token.IssuedAt = c.Token.IssuedAt
+	if token.Scope == "" {
+		token.Scope = c.Token.Scope
+	}
+
+	c.setToken(&token)
+	return c.SaveToken()
+}
+
+// GetGenres returns all genres, following the next links until every page
+// has been fetched.
+func (c *Client) GetGenres() ([]Genre, error) {
+	return c.GetGenresCtx(context.Background())
+}
+
+// GetGenresCtx is like GetGenres but uses ctx for its requests.
+func (c *Client) GetGenresCtx(ctx context.Context) (_ []Genre, err error) {
+	defer c.observeFetch("genres", time.Now(), &err)
+
+	var genres []Genre
+	pageURL := fmt.Sprintf("%s/catalog/genres/?per_page=%d", c.BaseURL, c.perPage(0))
+
+	for pageURL != "" {
+		var genreResp GenreResponse
+		if err := c.getJSON(ctx, pageURL, "get genres", &genreResp); err != nil {
+			return nil, err
+		}
 
+		genres = append(genres, genreResp.Results...)
+		if len(genreResp.Results) == 0 {
+			break
+		}
+
+		pageURL, err = c.resolveURL(genreResp.Next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return genres, nil
+}
+
+// GetSubGenres returns the sub-genres of a genre, e.g. "Peak Time / Driving"
+// for Techno, up to PageSize of them.
+func (c *Client) GetSubGenres(genreID int) ([]Genre, error) {
+	return c.GetSubGenresCtx(context.Background(), genreID)
+}
+
+// GetSubGenresCtx is like GetSubGenres but uses ctx for its requests.
+func (c *Client) GetSubGenresCtx(ctx context.Context, genreID int) ([]Genre, error) {
+	url := fmt.Sprintf("%s/catalog/genres/%d/sub-genres/?per_page=%d", c.BaseURL, genreID, c.perPage(0))
 	var genreResp GenreResponse
-	if err := json.NewDecoder(resp.Body).Decode(&genreResp); err != nil {
+	if err := c.getJSON(ctx, url, "get sub-genres", &genreResp); err != nil {
 		return nil, err
 	}
-
 	return genreResp.Results, nil
 }
 
 func (c *Client) GetTop100(genreID int) ([]Track, error) {
-	// Try the standard top 100 endpoint first
-	url := fmt.Sprintf("%s/catalog/genres/%d/top/100?per_page=100", c.BaseURL, genreID)
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetTop100Ctx(context.Background(), genreID)
+}
+
+// GetTop100Ctx is like GetTop100 but uses ctx for its requests.
+func (c *Client) GetTop100Ctx(ctx context.Context, genreID int) ([]Track, error) {
+	return c.GetGenreChartCtx(ctx, genreID, ChartTop100, 0)
+}
+
+// GetTop100ByName returns the Top 100 of the genre with the given name, slug
+// or ID. A partial name is accepted as long as it matches only one genre;
+// otherwise the error matches ErrGenreAmbiguous or ErrGenreNotFound.
+func (c *Client) GetTop100ByName(name string) ([]Track, error) {
+	return c.GetTop100ByNameCtx(context.Background(), name)
+}
+
+// GetTop100ByNameCtx is like GetTop100ByName but uses ctx for its requests.
+func (c *Client) GetTop100ByNameCtx(ctx context.Context, name string) ([]Track, error) {
+	genre, err := c.findGenre(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token.AccessToken)
+	return c.GetTop100Ctx(ctx, genre.ID)
+}
 
-	resp, err := c.doRequest(req)
-	if err != nil {
+// GetHypeTop100 returns the Hype Top 100 chart of a genre, which highlights
+// rising tracks from smaller artists.
+func (c *Client) GetHypeTop100(genreID int) ([]Track, error) {
+	return c.GetHypeTop100Ctx(context.Background(), genreID)
+}
+
+// GetHypeTop100Ctx is like GetHypeTop100 but uses ctx for its requests.
+func (c *Client) GetHypeTop100Ctx(ctx context.Context, genreID int) ([]Track, error) {
+	return c.GetGenreChartCtx(ctx, genreID, ChartHype100, 0)
+}
+
+// GetGenreChart fetches the top limit tracks of one of the genre charts
+// (ChartTop100 or ChartHype100), falling back to a genre search if the chart
+// endpoint fails. A limit of zero or less returns the whole chart.
+func (c *Client) GetGenreChart(genreID int, chartType string, limit int) ([]Track, error) {
+	return c.GetGenreChartCtx(context.Background(), genreID, chartType, limit)
+}
+
+// GetGenreChartCtx is like GetGenreChart but uses ctx for its requests.
+func (c *Client) GetGenreChartCtx(ctx context.Context, genreID int, chartType string, limit int) (_ []Track, err error) {
+	defer c.observeFetch("genre_chart", time.Now(), &err)
+
+	// Don't ask for more tracks than we need
+	perPage := c.perPage(limit)
+
+	// Try the standard top 100 endpoint first
+	url := fmt.Sprintf("%s/catalog/genres/%d/top/100?per_page=%d", c.BaseURL, genreID, perPage)
+	if chartType == ChartHype100 {
+		url += "&chart_type=hype"
+	}
+	ctx = c.withProgress(ctx)
+	var trackResp TrackResponse
+	var statusErr *StatusError
+	err = c.getJSON(ctx, url, "get "+chartType, &trackResp)
+	if err != nil && !errors.As(err, &statusErr) {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusOK {
-		var trackResp TrackResponse
-		if err := json.NewDecoder(resp.Body).Decode(&trackResp); err != nil {
-			return nil, err
+	if err == nil {
+		progressFrom(ctx).add(1)
+		if want := trackResp.Count; want > 0 && len(trackResp.Results) > 0 {
+			if limit > 0 {
+				want = min(want, limit)
+			}
+			progressFrom(ctx).setTotal((want + len(trackResp.Results) - 1) / len(trackResp.Results))
 		}
-		return trackResp.Results, nil
+		tracks := trackResp.Results
+		// With a small PageSize the chart spans several pages
+		if trackResp.Next != "" && len(tracks) > 0 && (limit <= 0 || len(tracks) < limit) {
+			nextURL, err := c.resolveURL(trackResp.Next)
+			if err != nil {
+				return nil, err
+			}
+			rest, err := c.followTrackPages(ctx, nextURL, limit-len(tracks))
+			tracks = append(tracks, rest...)
+			if err != nil {
+				return rankTracks(truncateTracks(tracks, limit)), partialTracksError(len(tracks), trackResp.Count, err)
+			}
+		}
+		return rankTracks(truncateTracks(tracks, limit)), nil
 	}
 
 	// Fallback to search if the specific endpoint fails (e.g. 404)
 	// Note: This is a heuristic fallback.
-	searchURL := fmt.Sprintf("%s/catalog/search?q=genre_id:%d&per_page=100&type=tracks", c.BaseURL, genreID)
-	req, err = http.NewRequest("GET", searchURL, nil)
-	if err != nil {
+	searchURL := fmt.Sprintf("%s/catalog/search?q=genre_id:%d&per_page=%d&type=tracks", c.BaseURL, genreID, perPage)
+	var searchResp SearchResponse
+	if err := c.getJSON(ctx, searchURL, "get "+chartType+" (fallback)", &searchResp); err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token.AccessToken)
 
-	resp, err = c.doRequest(req)
+	// Search results can list a track more than once
+	return rankTracks(truncateTracks(DedupTracks(searchResp.Tracks), limit)), nil
+}
+
+// truncateTracks returns at most limit tracks. A limit of zero or less keeps
+// all of them.
+func truncateTracks(tracks []Track, limit int) []Track {
+	if limit > 0 && len(tracks) > limit {
+		return tracks[:limit]
+	}
+	return tracks
+}
+
+// rankTracks sets the Rank of tracks that are in chart order.
+func rankTracks(tracks []Track) []Track {
+	for i := range tracks {
+		tracks[i].Rank = i + 1
+	}
+	return tracks
+}
+
+// GetTracksPaginated fetches up to limit tracks from the genre's Top 100 chart,
+// following the next page links until the limit is reached or the results run
+// out. A limit of zero or less fetches every page. If a page after the first
+// fails, the tracks of the pages before it are returned along with the error.
+func (c *Client) GetTracksPaginated(genreID, limit int) ([]Track, error) {
+	return c.GetTracksPaginatedCtx(context.Background(), genreID, limit)
+}
+
+// GetTracksPaginatedCtx is like GetTracksPaginated but uses ctx for its requests.
+func (c *Client) GetTracksPaginatedCtx(ctx context.Context, genreID, limit int) (_ []Track, err error) {
+	defer c.observeFetch("tracks_paginated", time.Now(), &err)
+
+	startURL := fmt.Sprintf("%s/catalog/genres/%d/top/100?per_page=%d", c.BaseURL, genreID, c.perPage(limit))
+	tracks, err := c.fetchTrackPages(ctx, startURL, limit)
+	return rankTracks(tracks), err
+}
+
+// pageFetchWorkers bounds the number of pages fetched concurrently.
+const pageFetchWorkers = 4
+
+// fetchTrackPages collects tracks from startURL and every following page.
+// When the first page reveals the total count and the pages are numbered,
+// the remaining pages are fetched concurrently. If a page fails, the tracks
+// of the pages before it are returned with the error.
+func (c *Client) fetchTrackPages(ctx context.Context, startURL string, limit int) ([]Track, error) {
+	ctx = c.withProgress(ctx)
+	first, err := c.fetchTrackPage(ctx, startURL)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	tracks := first.Results
+	if limit > 0 && len(tracks) >= limit {
+		return truncateTracks(tracks, limit), nil
+	}
+	if len(tracks) == 0 || first.Next == "" {
+		return tracks, nil
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to get top 100 (fallback): %s", string(body))
+	nextURL, err := c.resolveURL(first.Next)
+	if err != nil {
+		return tracks, partialTracksError(len(tracks), first.Count, err)
+	}
+
+	total := first.Count
+	if limit > 0 && limit < total {
+		total = limit
+	}
+	pageSize := len(first.Results)
+	next, err := url.Parse(nextURL)
+	if err != nil || total == 0 || next.Query().Get("page") != "2" {
+		// Without a count or page numbers only the next links can be followed
+		rest, err := c.followTrackPages(ctx, nextURL, limit-len(tracks))
+		tracks = truncateTracks(append(tracks, rest...), limit)
+		if err != nil {
+			return tracks, partialTracksError(len(tracks), first.Count, err)
+		}
+		return tracks, nil
+	}
+
+	pages := (total + pageSize - 1) / pageSize
+	progressFrom(ctx).setTotal(pages)
+	results := make([][]Track, pages)
+	fetched := make([]bool, pages)
+	results[0], fetched[0] = first.Results, true
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		jobs     = make(chan int)
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < min(pageFetchWorkers, pages-1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				pageURL := *next
+				q := pageURL.Query()
+				q.Set("page", strconv.Itoa(page+1))
+				pageURL.RawQuery = q.Encode()
+
+				resp, err := c.fetchTrackPage(ctx, pageURL.String())
+				if err != nil {
+					// Stop the other workers; their errors are only fallout
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[page], fetched[page] = resp.Results, true
+			}
+		}()
+	}
+	for page := 1; page < pages; page++ {
+		jobs <- page
+	}
+	close(jobs)
+	wg.Wait()
+
+	tracks = nil
+	for page, pageTracks := range results {
+		if !fetched[page] {
+			// Later pages can't be ranked without this one
+			break
+		}
+		tracks = append(tracks, pageTracks...)
+	}
+	tracks = truncateTracks(tracks, limit)
+	if firstErr != nil {
+		return tracks, partialTracksError(len(tracks), total, firstErr)
 	}
+	return tracks, nil
+}
 
-	// Search response structure might be different, usually has 'tracks' key
-	var searchResp struct {
-		Tracks []Track `json:"tracks"`
+// partialTracksError wraps the error that stopped a paginated fetch after got
+// of about total tracks. total is zero if it is unknown.
+func partialTracksError(got, total int, err error) error {
+	if total > 0 {
+		return fmt.Errorf("fetched %d of ~%d tracks: %w", got, total, err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+	return fmt.Errorf("fetched %d tracks: %w", got, err)
+}
+
+// followTrackPages collects tracks from pageURL and every page after it by
+// following the next links one at a time. On error the tracks collected so
+// far are returned with it.
+func (c *Client) followTrackPages(ctx context.Context, pageURL string, limit int) ([]Track, error) {
+	var tracks []Track
+	for pageURL != "" {
+		trackResp, err := c.fetchTrackPage(ctx, pageURL)
+		if err != nil {
+			return tracks, err
+		}
+
+		tracks = append(tracks, trackResp.Results...)
+		if limit > 0 && len(tracks) >= limit {
+			return truncateTracks(tracks, limit), nil
+		}
+		if len(trackResp.Results) == 0 {
+			break
+		}
+
+		pageURL, err = c.resolveURL(trackResp.Next)
+		if err != nil {
+			return tracks, err
+		}
+	}
+	return tracks, nil
+}
+
+// fetchTrackPage fetches a single page of tracks, and counts it for the
+// progress in ctx.
+func (c *Client) fetchTrackPage(ctx context.Context, pageURL string) (*TrackResponse, error) {
+	var trackResp TrackResponse
+	if err := c.getJSON(ctx, pageURL, "get tracks", &trackResp); err != nil {
 		return nil, err
 	}
+	progressFrom(ctx).add(1)
+	return &trackResp, nil
+}
 
-	return searchResp.Tracks, nil
+// resolveURL turns a possibly relative link returned by the API into an
+// absolute URL on the API host.
+func (c *Client) resolveURL(link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
 }
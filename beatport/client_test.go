@@ -1,14 +1,23 @@
 package beatport
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestFetchClientID(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	// Mock the JS file containing the client ID
 	jsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, `... API_CLIENT_ID: 'test-client-id' ...`)
@@ -52,6 +61,159 @@ func TestFetchClientID(t *testing.T) {
 	}
 }
 
+func TestFetchClientIDBundles(t *testing.T) {
+	tests := []struct {
+		name    string
+		docs    string
+		bundles map[string]string
+		want    string
+	}{
+		{
+			name: "minified double quotes",
+			docs: `<script src="/static/btprt/main.3f9a.js"></script>`,
+			bundles: map[string]string{
+				"/static/btprt/main.3f9a.js": `var e={API_URL:"https://api.beatport.com",API_CLIENT_ID:"minified-id"};`,
+			},
+			want: "minified-id",
+		},
+		{
+			name: "ID in a later bundle",
+			docs: `<script src="/static/btprt/vendor.a1.js"></script><script src='/static/btprt/app.b2.js'></script>`,
+			bundles: map[string]string{
+				"/static/btprt/vendor.a1.js": `!function(){console.log("vendor")}();`,
+				"/static/btprt/app.b2.js":    `config = { API_CLIENT_ID : 'later-id' }`,
+			},
+			want: "later-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/docs/" {
+					fmt.Fprint(w, tt.docs)
+					return
+				}
+				bundle, ok := tt.bundles[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprint(w, bundle)
+			}))
+			defer server.Close()
+
+			client, _ := NewClient()
+			client.BaseURL = server.URL
+			if err := client.FetchClientID(); err != nil {
+				t.Fatalf("FetchClientID failed: %v", err)
+			}
+			if client.ClientID != tt.want {
+				t.Errorf("Expected ClientID %q, got %q", tt.want, client.ClientID)
+			}
+		})
+	}
+}
+
+func TestFetchClientIDChallenge(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<html><head><title>Just a moment...</title></head><body><div id="cf-browser-verification"></div></body></html>`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+	if err := client.FetchClientID(); !errors.Is(err, ErrChallenge) {
+		t.Errorf("Expected ErrChallenge, got %v", err)
+	}
+}
+
+func TestNewClientWithTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		client, err := NewClientWithTimeout(timeout)
+		if err != nil {
+			t.Fatalf("NewClientWithTimeout(%v) failed: %v", timeout, err)
+		}
+		if client.HTTPClient.Timeout != timeout {
+			t.Errorf("Expected timeout %v, got %v", timeout, client.HTTPClient.Timeout)
+		}
+	}
+}
+
+func TestNewClientBaseURLFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIBaseURL, "http://localhost:8080/v4/")
+	t.Setenv(EnvAuthBaseURL, "")
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.BaseURL != "http://localhost:8080/v4" {
+		t.Errorf("Expected the API base URL from the environment, got %q", client.BaseURL)
+	}
+	if client.AuthURL != DefaultAuthBaseURL {
+		t.Errorf("Expected the default auth base URL, got %q", client.AuthURL)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+		fmt.Fprint(w, `<script src="/static/btprt/main.js"></script> API_CLIENT_ID: 'abc'`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.ClientIDTTL = 0
+	if err := client.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID failed: %v", err)
+	}
+
+	client.UserAgent = "beatport-top100-test/1.0"
+	if err := client.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID failed: %v", err)
+	}
+
+	if len(got) != 4 || got[0] != DefaultUserAgent || got[3] != "beatport-top100-test/1.0" {
+		t.Errorf("Unexpected User-Agent headers: %q", got)
+	}
+}
+
+func TestClientVersionHeader(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(ClientVersionHeader))
+		fmt.Fprint(w, `{"results": []}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	if _, err := client.GetGenres(); err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+
+	client.ClientVersion = ""
+	if _, err := client.GetGenres(); err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+
+	if want := ClientName + "/" + Version(); len(got) != 2 || got[0] != want || got[1] != "" {
+		t.Errorf("Expected %q and then no header, got %q", want, got)
+	}
+}
+
 func TestLogin(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/login/" {
@@ -84,6 +246,112 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestLoginVerificationCode(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"verification_required": true, "detail": "A verification code was sent to your email."}`)
+		case "/login/verify/":
+			var data map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&data); err != nil || data["code"] != "123456" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"detail": "Invalid code."}`)
+				return
+			}
+			fmt.Fprint(w, `{"username": "user"}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+
+	err := client.Login("user", "pass")
+	if !errors.Is(err, ErrVerificationRequired) {
+		t.Fatalf("Expected ErrVerificationRequired, got %v", err)
+	}
+	if err := client.SubmitVerificationCode("000000"); err == nil {
+		t.Errorf("Expected a wrong code to be rejected")
+	}
+	if err := client.SubmitVerificationCode("123456"); err != nil {
+		t.Errorf("SubmitVerificationCode failed: %v", err)
+	}
+}
+
+func TestLoginRejectsRevokedToken(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	loggedIn := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/my/account/":
+			if r.Header.Get("Authorization") != "Bearer revoked-token" {
+				t.Errorf("Unexpected Authorization header: %q", r.Header.Get("Authorization"))
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/o/token/":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "invalid_grant"}`)
+		case "/login/":
+			loggedIn = true
+			fmt.Fprint(w, `{"username": "user"}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Token = &OAuthToken{AccessToken: "revoked-token", RefreshToken: "revoked-refresh", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := client.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+
+	if err := client.Login("user", "pass"); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !loggedIn {
+		t.Errorf("Expected a fresh login after the saved token was rejected")
+	}
+}
+
+func TestLoginWithSavedTokenOnly(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/my/account/" {
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"username": "user"}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+
+	if err := client.Login("", ""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected ErrNoCredentials without a saved token, got %v", err)
+	}
+
+	client.Token = &OAuthToken{AccessToken: "saved-token", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := client.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	client.Token = nil
+	if err := client.Authenticate("", ""); err != nil {
+		t.Fatalf("Authenticate with a saved token failed: %v", err)
+	}
+}
+
 func TestGetGenres(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/catalog/genres/" {
@@ -108,6 +376,89 @@ func TestGetGenres(t *testing.T) {
 	}
 }
 
+func TestGetJSONStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "genre service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.MaxRetries = 0
+
+	_, err := client.GetGenres()
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a StatusError with status 503, got %v", err)
+	}
+	if want := "failed to get genres: genre service unavailable"; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("err = %q, want it to start with %q", err, want)
+	}
+}
+
+func TestGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected the access token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/v4/catalog/labels/1/tracks/" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("per_page") != "10" {
+			t.Errorf("Expected the query to be kept, got %s", r.URL)
+		}
+		fmt.Fprint(w, `{"results": [{"id": 7, "name": "Label Track"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL + "/v4"
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	var resp TrackResponse
+	if err := client.Get("/catalog/labels/1/tracks/?per_page=10", &resp); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Name != "Label Track" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	var statusErr *StatusError
+	if err := client.Get("catalog/unknown/", &resp); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a StatusError with status 404, got %v", err)
+	}
+}
+
+func TestGetGenresPaginated(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"next": "%s/catalog/genres/?per_page=100&page=2", "results": [{"id": 1, "name": "Techno"}, {"id": 2, "name": "House"}]}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{"next": null, "results": [{"id": 3, "name": "Trance"}]}`)
+		default:
+			t.Errorf("Unexpected page request: %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	genres, err := client.GetGenres()
+	if err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+	if len(genres) != 3 || genres[2].Name != "Trance" {
+		t.Errorf("Unexpected genres: %v", genres)
+	}
+}
+
 func TestGetTop100(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/catalog/genres/1/top/100" {
@@ -134,6 +485,36 @@ func TestGetTop100(t *testing.T) {
 	if len(tracks) != 1 || tracks[0].Name != "Track 1" {
 		t.Errorf("Unexpected tracks: %v", tracks)
 	}
+	if tracks[0].Rank != 1 {
+		t.Errorf("Expected rank 1, got %d", tracks[0].Rank)
+	}
+}
+
+func TestCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("price_code"); got != "EUR" {
+			t.Errorf("Expected price_code=EUR, got %q", got)
+		}
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Existing query parameters were lost: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": [{"id": 1, "price": {"code": "EUR", "value": 1.39}}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.Currency = "EUR"
+
+	tracks, err := client.GetTop100(1)
+	if err != nil {
+		t.Fatalf("GetTop100 failed: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Price == nil || tracks[0].Price.Code != "EUR" {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
 }
 
 func TestGetTop100Fallback(t *testing.T) {
@@ -169,3 +550,745 @@ func TestGetTop100Fallback(t *testing.T) {
 		t.Errorf("Unexpected tracks: %v", tracks)
 	}
 }
+
+func TestGetGenresRefreshesExpiredToken(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/o/token/":
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("Failed to parse form: %v", err)
+			}
+			if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old-refresh" {
+				t.Errorf("Unexpected refresh request: %v", r.Form)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "new-token", "refresh_token": "new-refresh", "expires_in": 36000}`)
+		case "/catalog/genres/":
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"results": [{"id": 1, "name": "Techno", "slug": "techno"}]}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Token = &OAuthToken{AccessToken: "old-token", RefreshToken: "old-refresh"}
+
+	genres, err := client.GetGenres()
+	if err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+	if len(genres) != 1 {
+		t.Errorf("Unexpected genres: %v", genres)
+	}
+	if client.Token.AccessToken != "new-token" || client.Token.RefreshToken != "new-refresh" {
+		t.Errorf("Token was not refreshed: %+v", client.Token)
+	}
+}
+
+func TestRefreshTokenExpired(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": "invalid_grant"}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Token = &OAuthToken{AccessToken: "old-token", RefreshToken: "expired-refresh"}
+
+	err := client.RefreshToken()
+	if !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Fatalf("Expected ErrRefreshTokenExpired, got %v", err)
+	}
+	if client.Token != nil {
+		t.Errorf("Expected token to be discarded, got %+v", client.Token)
+	}
+}
+
+func TestRefreshTokenExpiredEphemeral(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": "invalid_grant"}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Token = &OAuthToken{AccessToken: "old-token", RefreshToken: "expired-refresh"}
+	if err := client.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	client.Ephemeral = true
+
+	if err := client.RefreshToken(); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Fatalf("Expected ErrRefreshTokenExpired, got %v", err)
+	}
+	if client.Token != nil {
+		t.Errorf("Expected token to be discarded, got %+v", client.Token)
+	}
+	if _, err := os.Stat(client.TokenPath); err != nil {
+		t.Errorf("Expected the ephemeral client to leave the token file, got %v", err)
+	}
+}
+
+func TestRefreshTokenReusesClientID(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/o/token/" {
+			t.Errorf("Expected only a token request, got %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.Form.Get("client_id"); got != "saved-client-id" {
+			t.Errorf("Expected the saved client ID, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "new-token", "expires_in": 3600}`)
+	}))
+	defer server.Close()
+
+	saved, _ := NewClient()
+	saved.ClientID = "saved-client-id"
+	saved.setToken(&OAuthToken{AccessToken: "old-token", RefreshToken: "old-refresh"})
+	if err := saved.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	if err := client.LoadToken(); err != nil {
+		t.Fatalf("LoadToken failed: %v", err)
+	}
+	if err := client.RefreshToken(); err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if client.Token.AccessToken != "new-token" || client.Token.ClientID != "saved-client-id" {
+		t.Errorf("Unexpected token after refresh: %+v", client.Token)
+	}
+}
+
+func TestMaxTokenAge(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	client, _ := NewClient()
+	client.MaxTokenAge = 7 * 24 * time.Hour
+
+	client.setToken(&OAuthToken{AccessToken: "fresh", RefreshToken: "refresh", IssuedAt: time.Now().Add(-24 * time.Hour)})
+	if err := client.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	if err := client.LoadToken(); err != nil {
+		t.Fatalf("Expected a day old token to load, got %v", err)
+	}
+
+	client.setToken(&OAuthToken{AccessToken: "old", RefreshToken: "refresh", IssuedAt: time.Now().Add(-8 * 24 * time.Hour)})
+	if err := client.SaveToken(); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	if err := client.LoadToken(); !errors.Is(err, ErrTokenTooOld) {
+		t.Fatalf("Expected ErrTokenTooOld, got %v", err)
+	}
+	if _, err := os.Stat(client.TokenPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the old token to be deleted, got %v", err)
+	}
+}
+
+func TestRefreshTokenKeepsIssuedAt(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "new-token", "expires_in": 3600}`)
+	}))
+	defer server.Close()
+
+	issuedAt := time.Now().Add(-48 * time.Hour).Round(time.Second)
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Token = &OAuthToken{AccessToken: "old-token", RefreshToken: "old-refresh", IssuedAt: issuedAt}
+
+	if err := client.RefreshToken(); err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if !client.Token.IssuedAt.Equal(issuedAt) {
+		t.Errorf("Expected the refreshed token to keep IssuedAt %v, got %v", issuedAt, client.Token.IssuedAt)
+	}
+}
+
+func TestGetTokenSetsExpiry(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "test-token", "refresh_token": "test-refresh", "expires_in": 3600}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+
+	if err := client.GetToken("test-code"); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if !client.TokenValid() {
+		t.Errorf("Expected fresh token to be valid")
+	}
+	if remaining := time.Until(client.Token.ExpiresAt); remaining < 59*time.Minute || remaining > time.Hour {
+		t.Errorf("Unexpected expiry: %v", client.Token.ExpiresAt)
+	}
+
+	client.Token.ExpiresAt = time.Now().Add(30 * time.Second)
+	if client.TokenValid() {
+		t.Errorf("Expected token within a minute of expiry to be invalid")
+	}
+}
+
+func TestGetTracksPaginated(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"count": 3, "next": "%s/catalog/genres/1/top/100?page=2", "results": [{"id": 1}, {"id": 2}]}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{"count": 3, "next": "/catalog/genres/1/top/100?page=3", "results": [{"id": 3}]}`)
+		default:
+			t.Errorf("Unexpected page request: %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.GetTracksPaginated(1, 3)
+	if err != nil {
+		t.Fatalf("GetTracksPaginated failed: %v", err)
+	}
+	if len(tracks) != 3 || tracks[2].ID != 3 {
+		t.Errorf("Unexpected tracks: %v", tracks)
+	}
+
+	tracks, err = client.GetTracksPaginated(1, 1)
+	if err != nil {
+		t.Fatalf("GetTracksPaginated with limit failed: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].ID != 1 {
+		t.Errorf("Unexpected tracks with limit: %v", tracks)
+	}
+}
+
+func TestGetTracksPaginatedConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		mu.Lock()
+		requested[page]++
+		mu.Unlock()
+
+		n := 1
+		if page != "" {
+			n, _ = strconv.Atoi(page)
+		}
+		if n < 1 || n > 5 {
+			t.Errorf("Unexpected page request: %s", r.URL)
+		}
+
+		// 9 tracks, 2 per page
+		var results []string
+		for id := 2*n - 1; id <= min(2*n, 9); id++ {
+			results = append(results, fmt.Sprintf(`{"id": %d}`, id))
+		}
+		next := "null"
+		if n < 5 {
+			next = fmt.Sprintf(`"/catalog/genres/1/top/100?per_page=2&page=%d"`, n+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"count": 9, "next": %s, "results": [%s]}`, next, strings.Join(results, ", "))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+
+	tracks, err := client.GetTracksPaginated(1, 0)
+	if err != nil {
+		t.Fatalf("GetTracksPaginated failed: %v", err)
+	}
+	if len(tracks) != 9 {
+		t.Fatalf("Expected 9 tracks, got %d", len(tracks))
+	}
+	for i, track := range tracks {
+		if track.ID != i+1 || track.Rank != i+1 {
+			t.Errorf("Track %d out of order: %+v", i, track)
+		}
+	}
+	for page, n := range requested {
+		if n != 1 {
+			t.Errorf("Page %q requested %d times", page, n)
+		}
+	}
+}
+
+func TestGetTracksPaginatedPartial(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   string
+		wantErr string
+	}{
+		{"numbered pages", "6", "fetched 4 of ~6 tracks"},
+		{"next links only", "null", "fetched 4 tracks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := 1
+				if page := r.URL.Query().Get("page"); page != "" {
+					n, _ = strconv.Atoi(page)
+				}
+				if n == 3 {
+					http.Error(w, "gone", http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"count": %s, "next": "/catalog/genres/1/top/100?per_page=2&page=%d", "results": [{"id": %d}, {"id": %d}]}`,
+					tt.count, n+1, 2*n-1, 2*n)
+			}))
+			defer server.Close()
+
+			client, _ := NewClient()
+			client.BaseURL = server.URL
+			client.Token = &OAuthToken{AccessToken: "test-token"}
+			client.RateLimit = 0
+
+			tracks, err := client.GetTracksPaginated(1, 0)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if len(tracks) != 4 {
+				t.Fatalf("Expected the 4 tracks before the failing page, got %d", len(tracks))
+			}
+			for i, track := range tracks {
+				if track.ID != i+1 || track.Rank != i+1 {
+					t.Errorf("Track %d out of order: %+v", i, track)
+				}
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	var perPage []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perPage = append(perPage, r.URL.Query().Get("per_page"))
+		n := 1
+		if page := r.URL.Query().Get("page"); page != "" {
+			n, _ = strconv.Atoi(page)
+		}
+		next := "null"
+		if n < 3 {
+			next = fmt.Sprintf(`"/catalog/genres/1/top/100?per_page=2&page=%d"`, n+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"next": %s, "results": [{"id": %d}, {"id": %d}]}`, next, 2*n-1, 2*n)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+	client.PageSize = 2
+
+	tracks, err := client.GetTop100(1)
+	if err != nil {
+		t.Fatalf("GetTop100 failed: %v", err)
+	}
+	if len(tracks) != 6 || tracks[5].Rank != 6 {
+		t.Errorf("Expected the 6 tracks of all 3 pages, got %+v", tracks)
+	}
+	if perPage[0] != "2" {
+		t.Errorf("Expected per_page=2, got %q", perPage[0])
+	}
+
+	for size, want := range map[int]int{0: DefaultPageSize, -1: DefaultPageSize, 500: MaxPageSize, 25: 25} {
+		client.PageSize = size
+		if got := client.perPage(0); got != want {
+			t.Errorf("PageSize %d: expected per_page %d, got %d", size, want, got)
+		}
+	}
+}
+
+func TestNewClientWithHTTPClient(t *testing.T) {
+	hc := &http.Client{Timeout: 5 * time.Second}
+
+	client, err := NewClientWithHTTPClient(hc)
+	if err != nil {
+		t.Fatalf("NewClientWithHTTPClient failed: %v", err)
+	}
+	if client.HTTPClient != hc {
+		t.Errorf("Expected the provided http client to be used")
+	}
+	if hc.Jar == nil {
+		t.Errorf("Expected a cookie jar to be added")
+	}
+}
+
+func TestAuthorizeKeepsCheckRedirect(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"username": "user"}`)
+		case "/o/authorize/":
+			w.Header().Set("Location", "/o/post-message/?code=test-code")
+			w.WriteHeader(http.StatusFound)
+		case "/o/token/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	errPolicy := errors.New("custom redirect policy")
+	hc := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error { return errPolicy },
+	}
+	client, _ := NewClientWithHTTPClient(hc)
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+
+	if err := client.Login("user", "pass"); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	code, err := client.Authorize()
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if code != "test-code" {
+		t.Errorf("Expected code test-code, got %q", code)
+	}
+	if err := client.GetToken(code); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	if hc.CheckRedirect == nil || hc.CheckRedirect(nil, nil) != errPolicy {
+		t.Errorf("Expected the http client's CheckRedirect to be left alone")
+	}
+}
+
+func TestGetGenresCtxCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetGenresCtx(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the retry backoff to be aborted, took %v", elapsed)
+	}
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"non_field_errors": ["Unable to log in with provided credentials."]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+
+	err := client.Login("user", "wrong")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected AuthError with status 400, got %v", err)
+	}
+}
+
+func TestLoginErrorWithOKStatus(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error": "invalid_grant", "error_description": "Invalid credentials given."}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+
+	err := client.Login("user", "wrong")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.Body != "Invalid credentials given." {
+		t.Errorf("Expected the error description from the body, got %v", err)
+	}
+}
+
+func TestFetchClientIDUsesCache(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/docs/" {
+			fmt.Fprint(w, `<html><script src="/static/btprt/main.js"></script></html>`)
+		} else {
+			fmt.Fprint(w, `... API_CLIENT_ID: 'test-client-id' ...`)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	if err := client.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID failed: %v", err)
+	}
+
+	cached, _ := NewClient()
+	cached.BaseURL = server.URL
+	if err := cached.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID from cache failed: %v", err)
+	}
+	if cached.ClientID != "test-client-id" {
+		t.Errorf("Expected cached ClientID 'test-client-id', got '%s'", cached.ClientID)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the cached ID to skip scraping, got %d requests", requests)
+	}
+
+	stale, _ := NewClient()
+	stale.BaseURL = server.URL
+	stale.ClientIDTTL = 0
+	if err := stale.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID without cache failed: %v", err)
+	}
+	if requests != 4 {
+		t.Errorf("Expected a zero TTL to bypass the cache, got %d requests", requests)
+	}
+}
+
+func TestGetSubGenres(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/genres/6/sub-genres/" {
+			t.Errorf("Expected path /catalog/genres/6/sub-genres/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": [{"id": 2, "name": "Peak Time / Driving", "slug": "peak-time-driving"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	subGenres, err := client.GetSubGenres(6)
+	if err != nil {
+		t.Fatalf("GetSubGenres failed: %v", err)
+	}
+	if len(subGenres) != 1 || subGenres[0].Name != "Peak Time / Driving" {
+		t.Errorf("Unexpected sub-genres: %v", subGenres)
+	}
+}
+
+func TestGetHypeTop100(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/genres/1/top/100" {
+			t.Errorf("Expected path /catalog/genres/1/top/100, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("chart_type") != "hype" {
+			t.Errorf("Expected chart_type=hype")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": [{"id": 201, "name": "Hype Track", "artists": [{"name": "Artist 2"}], "mix_name": "Original Mix"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.GetHypeTop100(1)
+	if err != nil {
+		t.Fatalf("GetHypeTop100 failed: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Hype Track" {
+		t.Errorf("Unexpected tracks: %v", tracks)
+	}
+}
+
+func TestGetGenreChartLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("Expected per_page=2, got %s", r.URL.Query().Get("per_page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": [{"id": 1}, {"id": 2}, {"id": 3}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.GetGenreChart(1, ChartTop100, 2)
+	if err != nil {
+		t.Fatalf("GetGenreChart failed: %v", err)
+	}
+	if len(tracks) != 2 || tracks[1].ID != 2 {
+		t.Errorf("Unexpected tracks: %v", tracks)
+	}
+}
+
+func TestDoRequestHonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": [{"id": 1, "name": "Techno", "slug": "techno"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	start := time.Now()
+	genres, err := client.GetGenres()
+	if err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+	if len(genres) != 1 || attempts != 2 {
+		t.Errorf("Expected a retry after 429, got %d attempts and genres %v", attempts, genres)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed >= 2*time.Second {
+		t.Errorf("Expected to wait for Retry-After (1s), waited %v", elapsed)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results": []}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 20
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetGenres(); err != nil {
+			t.Fatalf("GetGenres failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be throttled to 20/s, took %v", elapsed)
+	}
+}
+
+func TestDoRequestRateLimited(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	_, err := client.GetGenres()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if attempts != DefaultMaxRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", DefaultMaxRetries+1, attempts)
+	}
+}
+
+func TestDoRequestRetryConfig(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.MaxRetries = 5
+	client.RetryBaseDelay = time.Millisecond
+	client.RateLimit = 0
+
+	start := time.Now()
+	if _, err := client.GetGenres(); err == nil {
+		t.Fatal("Expected GetGenres to fail")
+	}
+	if attempts != 6 {
+		t.Errorf("Expected 6 attempts, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected millisecond backoff, took %v", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+package beatport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// savedCookie is the on-disk form of a session cookie. The cookie jar only
+// exposes names and values, so that is all that is kept.
+type savedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cookieURLs returns the URLs whose cookies make up the login session.
+func (c *Client) cookieURLs() []*url.URL {
+	var urls []*url.URL
+	for _, raw := range []string{c.BaseURL, c.AuthURL} {
+		if u, err := url.Parse(raw); err == nil {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// SaveCookies writes the session cookies to CookiesPath so the login session
+// can be reused by a later run. It does nothing if the client is Ephemeral.
+func (c *Client) SaveCookies() error {
+	if c.HTTPClient.Jar == nil || c.Ephemeral {
+		return nil
+	}
+
+	saved := make(map[string][]savedCookie)
+	for _, u := range c.cookieURLs() {
+		for _, cookie := range c.HTTPClient.Jar.Cookies(u) {
+			saved[u.String()] = append(saved[u.String()], savedCookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+
+	err := WriteFileAtomic(c.CookiesPath, 0o600, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(saved)
+	})
+	if err != nil {
+		return err
+	}
+	c.cookiesDirty.Store(false)
+	return nil
+}
+
+// LoadCookies restores the session cookies saved by SaveCookies.
+func (c *Client) LoadCookies() error {
+	if c.HTTPClient.Jar == nil {
+		return nil
+	}
+
+	file, err := os.Open(c.CookiesPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var saved map[string][]savedCookie
+	if err := json.NewDecoder(file).Decode(&saved); err != nil {
+		return err
+	}
+
+	for rawURL, cookies := range saved {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		httpCookies := make([]*http.Cookie, 0, len(cookies))
+		for _, cookie := range cookies {
+			httpCookies = append(httpCookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+		}
+		c.HTTPClient.Jar.SetCookies(u, httpCookies)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package beatport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCookiesRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "abc123", Path: "/"})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), CookiesFile)
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.CookiesPath = path
+
+	resp, err := client.HTTPClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if err := client.SaveCookies(); err != nil {
+		t.Fatalf("SaveCookies failed: %v", err)
+	}
+
+	restored, _ := NewClient()
+	restored.BaseURL = server.URL
+	restored.AuthURL = server.URL
+	restored.CookiesPath = path
+	if err := restored.LoadCookies(); err != nil {
+		t.Fatalf("LoadCookies failed: %v", err)
+	}
+
+	u := client.cookieURLs()[0]
+	cookies := restored.HTTPClient.Jar.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != "sessionid" || cookies[0].Value != "abc123" {
+		t.Errorf("Unexpected restored cookies: %v", cookies)
+	}
+}
+
+func TestCloseSavesChangedCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "abc123", Path: "/"})
+		w.Write([]byte(`<script src="/static/btprt/main.js"></script> API_CLIENT_ID: 'abc'`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.CookiesPath = filepath.Join(dir, CookiesFile)
+	client.ClientIDPath = filepath.Join(dir, ClientIDFile)
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(client.CookiesPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no cookies file without a session, got %v", err)
+	}
+
+	if err := client.FetchClientID(); err != nil {
+		t.Fatalf("FetchClientID failed: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(client.CookiesPath); err != nil {
+		t.Errorf("Expected Close to save the new session cookies: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package beatport
+
+// DedupTracks returns the tracks with every track ID kept only once, at its
+// first, and so best ranked, occurrence. The order of the remaining tracks is
+// unchanged; their Rank is left as it was.
+func DedupTracks(tracks []Track) []Track {
+	seen := make(map[int]bool, len(tracks))
+	unique := make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		if seen[track.ID] {
+			continue
+		}
+		seen[track.ID] = true
+		unique = append(unique, track)
+	}
+	return unique
+}
@@ -0,0 +1,25 @@
+package beatport
+
+import "testing"
+
+func TestDedupTracks(t *testing.T) {
+	tracks := []Track{{ID: 3, Name: "first"}, {ID: 1}, {ID: 3, Name: "second"}, {ID: 2}, {ID: 1}, {ID: 3}}
+
+	got := DedupTracks(tracks)
+
+	want := []int{3, 1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d tracks, got %+v", len(want), got)
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("Track %d: expected ID %d, got %d", i, id, got[i].ID)
+		}
+	}
+	if got[0].Name != "first" {
+		t.Errorf("Expected the first occurrence to be kept, got %+v", got[0])
+	}
+	if len(DedupTracks(nil)) != 0 {
+		t.Errorf("Expected no tracks for nil input")
+	}
+}
@@ -0,0 +1,73 @@
+package beatport
+
+// ChartDiff is the difference between two fetches of a chart, matched by
+// track ID.
+type ChartDiff struct {
+	// New are the tracks that entered the chart, in chart order.
+	New []Track `json:"new"`
+	// Dropped are the tracks that left the chart, in their old chart order.
+	Dropped []Track `json:"dropped"`
+	// Moves are the tracks on both charts, in their new chart order.
+	Moves []ChartMove `json:"moves"`
+}
+
+// Changed reports whether any track entered, left or moved on the chart.
+func (d ChartDiff) Changed() bool {
+	if len(d.New) > 0 || len(d.Dropped) > 0 {
+		return true
+	}
+	for _, m := range d.Moves {
+		if m.Change() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ChartMove is the change in position of a track that is on both charts.
+type ChartMove struct {
+	Track   Track `json:"track"`
+	OldRank int   `json:"old_rank"`
+	NewRank int   `json:"new_rank"`
+}
+
+// Change returns how many places the track climbed. It is negative if the
+// track fell and zero if it stayed put.
+func (m ChartMove) Change() int {
+	return m.OldRank - m.NewRank
+}
+
+// DiffCharts compares two fetches of the same chart. Positions are taken from
+// the tracks' Rank, or from their order for tracks without one.
+func DiffCharts(old, current []Track) ChartDiff {
+	oldRanks := make(map[int]int, len(old))
+	for i, track := range old {
+		oldRanks[track.ID] = chartRank(track, i)
+	}
+	newIDs := make(map[int]bool, len(current))
+
+	var diff ChartDiff
+	for i, track := range current {
+		newIDs[track.ID] = true
+		oldRank, ok := oldRanks[track.ID]
+		if !ok {
+			diff.New = append(diff.New, track)
+			continue
+		}
+		diff.Moves = append(diff.Moves, ChartMove{Track: track, OldRank: oldRank, NewRank: chartRank(track, i)})
+	}
+	for _, track := range old {
+		if !newIDs[track.ID] {
+			diff.Dropped = append(diff.Dropped, track)
+		}
+	}
+	return diff
+}
+
+// chartRank returns the rank of the track at index i of a chart.
+func chartRank(track Track, i int) int {
+	if track.Rank > 0 {
+		return track.Rank
+	}
+	return i + 1
+}
@@ -0,0 +1,44 @@
+package beatport
+
+import "testing"
+
+func TestDiffCharts(t *testing.T) {
+	old := []Track{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	current := []Track{{ID: 3}, {ID: 1}, {ID: 5}, {ID: 2}}
+
+	diff := DiffCharts(old, current)
+
+	if len(diff.New) != 1 || diff.New[0].ID != 5 {
+		t.Errorf("Unexpected new tracks: %+v", diff.New)
+	}
+	if len(diff.Dropped) != 1 || diff.Dropped[0].ID != 4 {
+		t.Errorf("Unexpected dropped tracks: %+v", diff.Dropped)
+	}
+
+	changes := map[int]int{}
+	for _, m := range diff.Moves {
+		changes[m.Track.ID] = m.Change()
+	}
+	want := map[int]int{3: 2, 1: -1, 2: -2}
+	for id, change := range want {
+		if got, ok := changes[id]; !ok || got != change {
+			t.Errorf("Track %d: expected change %d, got %d (present: %v)", id, change, got, ok)
+		}
+	}
+	if len(diff.Moves) != len(want) {
+		t.Errorf("Expected %d moves, got %d", len(want), len(diff.Moves))
+	}
+}
+
+func TestChartDiffChanged(t *testing.T) {
+	chart := []Track{{ID: 1}, {ID: 2}}
+	if DiffCharts(chart, chart).Changed() {
+		t.Error("Expected an identical chart to be unchanged")
+	}
+	if !DiffCharts(chart, []Track{{ID: 2}, {ID: 1}}).Changed() {
+		t.Error("Expected swapped tracks to be a change")
+	}
+	if !DiffCharts(chart, []Track{{ID: 1}, {ID: 3}}).Changed() {
+		t.Error("Expected a replaced track to be a change")
+	}
+}
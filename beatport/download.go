@@ -0,0 +1,158 @@
+package beatport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNoPreview is returned by DownloadPreview for tracks without a
+	// preview clip.
+	ErrNoPreview = errors.New("track has no preview")
+	// ErrNoArtwork is returned by DownloadArtwork for tracks without artwork.
+	ErrNoArtwork = errors.New("track has no artwork")
+)
+
+// DownloadPreview downloads the preview clip of a track into dir as
+// "<artist> - <name> (<mix>).mp3" and returns the path of the written file.
+func (c *Client) DownloadPreview(track Track, dir string) (string, error) {
+	return c.DownloadPreviewCtx(context.Background(), track, dir)
+}
+
+// DownloadPreviewCtx is like DownloadPreview but uses ctx for its requests.
+func (c *Client) DownloadPreviewCtx(ctx context.Context, track Track, dir string) (string, error) {
+	if track.PreviewURL == "" {
+		return "", ErrNoPreview
+	}
+
+	path := filepath.Join(dir, trackFilename(track)+".mp3")
+	if err := c.download(ctx, track.PreviewURL, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// DownloadArtwork downloads the cover image of a track's release into dir as
+// "<artist> - <name> (<mix>)" with the image's extension, and returns the
+// path of the written file.
+func (c *Client) DownloadArtwork(track Track, dir string) (string, error) {
+	return c.DownloadArtworkCtx(context.Background(), track, dir)
+}
+
+// DownloadArtworkCtx is like DownloadArtwork but uses ctx for its requests.
+func (c *Client) DownloadArtworkCtx(ctx context.Context, track Track, dir string) (string, error) {
+	if track.ArtworkURL == "" {
+		return "", ErrNoArtwork
+	}
+
+	ext := ".jpg"
+	if u, err := url.Parse(track.ArtworkURL); err == nil && path.Ext(u.Path) != "" {
+		ext = path.Ext(u.Path)
+	}
+	file := filepath.Join(dir, trackFilename(track)+ext)
+	if err := c.download(ctx, track.ArtworkURL, file); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// trackFilename returns "<artist> - <name> (<mix>)" for the track, safe to
+// use as a file name.
+func trackFilename(track Track) string {
+	artistName := ""
+	if len(track.Artists) > 0 {
+		artistName = track.Artists[0].Name
+	}
+	return SafeFilename(fmt.Sprintf("%s - %s (%s)", artistName, track.Name, track.MixTitle()))
+}
+
+// download saves the resource at rawURL to path. The file is removed again if
+// the download fails halfway.
+func (c *Client) download(ctx context.Context, rawURL, path string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// maxFilenameLength is the longest name, in bytes, SafeFilename returns. It
+// leaves room for an extension within the 255 byte limit of most file
+// systems.
+const maxFilenameLength = 200
+
+// reservedFilenames are the device names Windows doesn't allow as file
+// names, with or without an extension.
+var reservedFilenames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// SafeFilename turns s into a name that can be used for a file on Windows,
+// macOS and Linux. Characters that aren't allowed (/ \ : * ? " < > |) and
+// invalid UTF-8 are replaced with "_", control characters are removed,
+// leading and trailing spaces and trailing dots are trimmed and the name is
+// cut to 200 bytes, so an extension can still be appended. Windows device
+// names get a "_" appended ("CON_", "nul_.txt"), and an empty name becomes
+// "_".
+func SafeFilename(s string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', utf8.RuneError:
+			return '_'
+		}
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+	if len(name) > maxFilenameLength {
+		cut := maxFilenameLength
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+	name = strings.TrimRight(strings.TrimSpace(name), ". ")
+
+	base, _, _ := strings.Cut(name, ".")
+	if slices.ContainsFunc(reservedFilenames, func(r string) bool { return strings.EqualFold(r, strings.TrimSpace(base)) }) {
+		name = base + "_" + name[len(base):]
+	}
+	if name == "" {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+package beatport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownloadPreview(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "mp3-data")
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	dir := t.TempDir()
+	track := Track{
+		Name:       "Track: 1",
+		MixName:    "Original Mix",
+		Artists:    []Artist{{Name: "AC/DC"}},
+		PreviewURL: server.URL + "/preview.mp3",
+	}
+
+	path, err := client.DownloadPreview(track, dir)
+	if err != nil {
+		t.Fatalf("DownloadPreview failed: %v", err)
+	}
+
+	expected := filepath.Join(dir, "AC_DC - Track_ 1 (Original Mix).mp3")
+	if path != expected {
+		t.Errorf("Expected path %q, got %q", expected, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "mp3-data" {
+		t.Errorf("Unexpected file contents %q: %v", data, err)
+	}
+
+	if _, err := client.DownloadPreview(Track{Name: "No Preview"}, dir); !errors.Is(err, ErrNoPreview) {
+		t.Errorf("Expected ErrNoPreview, got %v", err)
+	}
+}
+
+func TestDownloadArtwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/image_size/500x500/cover.png" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "png-data")
+	}))
+	defer server.Close()
+
+	var track Track
+	data := `{"name": "Pulse", "mix_name": "Original Mix", "artists": [{"name": "Fixture Collective"}],
+		"release": {"image": {"uri": "` + server.URL + `/image_size/1400x1400/cover.png", "dynamic_uri": "` + server.URL + `/image_size/{w}x{h}/cover.png"}}}`
+	if err := json.Unmarshal([]byte(data), &track); err != nil {
+		t.Fatal(err)
+	}
+
+	client, _ := NewClient()
+	dir := t.TempDir()
+	path, err := client.DownloadArtwork(track, dir)
+	if err != nil {
+		t.Fatalf("DownloadArtwork failed: %v", err)
+	}
+
+	expected := filepath.Join(dir, "Fixture Collective - Pulse (Original Mix).png")
+	if path != expected {
+		t.Errorf("Expected path %q, got %q", expected, path)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil || string(contents) != "png-data" {
+		t.Errorf("Unexpected file contents %q: %v", contents, err)
+	}
+
+	if _, err := client.DownloadArtwork(Track{Name: "No Artwork"}, dir); !errors.Is(err, ErrNoArtwork) {
+		t.Errorf("Expected ErrNoArtwork, got %v", err)
+	}
+}
+
+func TestSafeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Artist - Title (Original Mix)", "Artist - Title (Original Mix)"},
+		{"reserved characters", `Artist / Title: Mix?`, "Artist _ Title_ Mix_"},
+		{"all reserved characters", `a/b\c:d*e?f"g<h>i|j`, "a_b_c_d_e_f_g_h_i_j"},
+		{"control characters", "Tab\tNew\nLine\x7f", "TabNewLine"},
+		{"trailing dots and spaces", "  Vol. 2...  ", "Vol. 2"},
+		{"windows device name", "CON", "CON_"},
+		{"windows device name with extension", "nul.txt", "nul_.txt"},
+		{"device name as part of a name", "Console", "Console"},
+		{"empty", "", "_"},
+		{"only dots", "...", "_"},
+		{"invalid UTF-8", "a\xffb", "a_b"},
+		{"long", strings.Repeat("a", 300), strings.Repeat("a", maxFilenameLength)},
+		{"long multibyte", strings.Repeat("é", 150), strings.Repeat("é", maxFilenameLength/2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeFilename(tt.in); got != tt.want {
+				t.Errorf("SafeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package beatport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxDumpBody is how much of a request or response body is written to a
+// dump, so downloading a preview doesn't dump the whole clip.
+const maxDumpBody = 1 << 20
+
+// redacted replaces secrets in dumps.
+const redacted = "[REDACTED]"
+
+// secretParams are the form fields, query parameters and JSON keys whose
+// values are never written to a dump.
+var secretParams = []string{"password", "access_token", "refresh_token", "id_token", "code", "code_verifier", "client_secret"}
+
+// secretHeaders are the headers whose values are never written to a dump.
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// reSecretJSON matches a secret JSON string value, with its key.
+var reSecretJSON = regexp.MustCompile(`("(?:` + strings.Join(secretParams, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// dumpAttempt writes the request and response of an HTTP attempt to a new
+// file in DumpDir, with credentials redacted. reqBody is the request body,
+// and the response body is read into the dump and restored for the caller.
+// Failing to write the dump is logged but doesn't fail the request.
+func (c *Client) dumpAttempt(req *http.Request, reqBody []byte, resp *http.Response, respErr error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", req.Method, redactURL(req.URL))
+	writeDumpHeaders(&buf, req.Header)
+	writeDumpBody(&buf, req.Header, reqBody)
+
+	buf.WriteString("\n--- response ---\n")
+	if respErr != nil {
+		fmt.Fprintf(&buf, "error: %v\n", respErr)
+	} else {
+		fmt.Fprintln(&buf, strings.TrimSpace(resp.Proto+" "+resp.Status))
+		writeDumpHeaders(&buf, resp.Header)
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxDumpBody))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		if err != nil {
+			fmt.Fprintf(&buf, "\nerror reading body: %v\n", err)
+		}
+		writeDumpBody(&buf, resp.Header, body)
+	}
+
+	name := fmt.Sprintf("%s-%04d-%s.txt", time.Now().UTC().Format("20060102T150405.000000"), c.dumpSeq.Add(1), req.Method)
+	path := filepath.Join(c.DumpDir, name)
+	if err := os.MkdirAll(c.DumpDir, 0o700); err != nil {
+		c.logger().Debug("failed to dump request", "path", path, "error", err)
+		return
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		c.logger().Debug("failed to dump request", "path", path, "error", err)
+	}
+}
+
+// dumpRequestBody returns a copy of the request body to dump, or nil if it
+// can't be read without consuming it.
+func dumpRequestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(io.LimitReader(body, maxDumpBody))
+	return data
+}
+
+func writeDumpHeaders(buf *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\n", key, redactHeader(key, value))
+		}
+	}
+}
+
+func writeDumpBody(buf *bytes.Buffer, header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	buf.WriteString("\n")
+	buf.Write(redactBody(header.Get("Content-Type"), body))
+	buf.WriteString("\n")
+}
+
+// redactHeader returns the value of a header with any credentials in it
+// replaced. The scheme of an Authorization header is kept, so the dump
+// still shows which kind of credentials were sent.
+func redactHeader(key, value string) string {
+	if slices.ContainsFunc(secretHeaders, func(h string) bool { return strings.EqualFold(h, key) }) {
+		if scheme, _, ok := strings.Cut(value, " "); ok && strings.EqualFold(key, "Authorization") {
+			return scheme + " " + redacted
+		}
+		return redacted
+	}
+	if strings.EqualFold(key, "Location") {
+		if u, err := url.Parse(value); err == nil {
+			return redactURL(u)
+		}
+	}
+	return value
+}
+
+// redactURL returns u with the values of secret query parameters replaced.
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	redactedURL.RawQuery = redactValues(u.Query()).Encode()
+	return redactedURL.Redacted()
+}
+
+func redactValues(values url.Values) url.Values {
+	for _, key := range secretParams {
+		if values.Has(key) {
+			values.Set(key, redacted)
+		}
+	}
+	return values
+}
+
+// redactBody returns the body with the values of secret form fields or JSON
+// keys replaced.
+func redactBody(contentType string, body []byte) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return []byte(redactValues(values).Encode())
+		}
+	}
+	return reSecretJSON.ReplaceAll(body, []byte(`$1"`+redacted+`"`))
+}
@@ -0,0 +1,76 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpDirRedactsSecrets(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login/":
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "secret-session"})
+			fmt.Fprint(w, `{"username": "dj"}`)
+		case "/o/token/":
+			fmt.Fprint(w, `{"access_token": "secret-access", "refresh_token": "secret-refresh", "expires_in": 3600}`)
+		case "/catalog/genres/":
+			fmt.Fprint(w, `{"results": [{"id": 5, "name": "House", "slug": "house"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	dumpDir := filepath.Join(t.TempDir(), "dump")
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.DumpDir = dumpDir
+
+	if err := client.Login("dj", `hunter"2`); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if err := client.GetToken("secret-code"); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if client.Token.AccessToken != "secret-access" {
+		t.Errorf("Dumping consumed the response: %+v", client.Token)
+	}
+	genres, err := client.GetGenres()
+	if err != nil || len(genres) != 1 {
+		t.Fatalf("GetGenres = %v, %v", genres, err)
+	}
+
+	entries, err := os.ReadDir(dumpDir)
+	if err != nil {
+		t.Fatalf("Failed to read dump dir: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected a dump per request, got %d files", len(entries))
+	}
+	var dumps strings.Builder
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dumpDir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dumps.Write(data)
+	}
+	for _, secret := range []string{"hunter", "secret-"} {
+		if strings.Contains(dumps.String(), secret) {
+			t.Errorf("Dump contains %q:\n%s", secret, dumps.String())
+		}
+	}
+	for _, want := range []string{`"username":"dj"`, "Authorization: Bearer [REDACTED]", "code=%5BREDACTED%5D", `"name": "House"`} {
+		if !strings.Contains(dumps.String(), want) {
+			t.Errorf("Expected the dump to contain %q:\n%s", want, dumps.String())
+		}
+	}
+}
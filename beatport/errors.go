@@ -0,0 +1,89 @@
+package beatport
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidCredentials is returned when Beatport rejects the username or
+	// password used to log in.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrVerificationRequired is returned by Login when Beatport wants the
+	// verification code it emailed before completing the login. Pass the code
+	// to SubmitVerificationCode.
+	ErrVerificationRequired = errors.New("verification code required")
+
+	// ErrRefreshTokenExpired is returned when the refresh token has been rejected
+	// by the API. The saved token is discarded and the user must log in again.
+	ErrRefreshTokenExpired = errors.New("refresh token expired, please log in again")
+
+	// ErrNoToken is returned when an operation needs a token but the client has
+	// not logged in yet.
+	ErrNoToken = errors.New("no token, please log in")
+
+	// ErrTokenTooOld is returned by LoadToken when the saved token was
+	// issued longer than MaxTokenAge ago.
+	ErrTokenTooOld = errors.New("token is older than the maximum token age, please log in again")
+
+	// ErrNoCredentials is returned by Login when there is no usable saved
+	// token and no username or password to log in with.
+	ErrNoCredentials = errors.New("no saved token, username and password required")
+
+	// ErrChallenge is returned when Beatport answers with a Cloudflare
+	// challenge page instead of the requested page, which happens when it
+	// suspects the request comes from a bot.
+	ErrChallenge = errors.New("blocked by a Cloudflare challenge page; set UserAgent to that of a real browser, or pass a browser session's cookies in cookies.json")
+
+	// ErrGenreNotFound is returned when no genre matches the requested name.
+	ErrGenreNotFound = errors.New("genre not found")
+
+	// ErrGenreAmbiguous is returned when a partial genre name matches more
+	// than one genre.
+	ErrGenreAmbiguous = errors.New("genre is ambiguous")
+
+	// ErrRateLimited is returned when the API keeps responding with
+	// 429 Too Many Requests after all retries.
+	ErrRateLimited = errors.New("rate limited by the Beatport API")
+)
+
+// AuthError describes a failed step of the authentication flow. Err, when set,
+// is one of the sentinel errors above and can be matched with errors.Is.
+type AuthError struct {
+	Op         string
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (e *AuthError) Error() string {
+	msg := e.Op + " failed"
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is returned when the API responds to a request with a status
+// other than 200 OK. Body is the response body, which usually explains the
+// error.
+type StatusError struct {
+	Op         string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to %s: %s", e.Op, e.Body)
+}
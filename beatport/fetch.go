@@ -0,0 +1,55 @@
+package beatport
+
+import (
+	"context"
+	"errors"
+)
+
+// Authenticate runs the whole login flow: it logs in (or reuses the saved
+// token), authorizes the client and exchanges the code for a token.
+func (c *Client) Authenticate(username, password string) error {
+	return c.AuthenticateCtx(context.Background(), username, password)
+}
+
+// AuthenticateCtx is like Authenticate but uses ctx for its requests.
+func (c *Client) AuthenticateCtx(ctx context.Context, username, password string) (err error) {
+	defer func() {
+		// Neither is a rejection: a verification code is part of a successful
+		// login, and without credentials there was nothing to try
+		if err != nil && !errors.Is(err, ErrVerificationRequired) && !errors.Is(err, ErrNoCredentials) {
+			c.observeAuthFailure("authenticate", err)
+		}
+	}()
+
+	if err := c.LoginCtx(ctx, username, password); err != nil {
+		return err
+	}
+	code, err := c.AuthorizeCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return c.GetTokenCtx(ctx, code)
+}
+
+// FetchTop100 logs in with a new client and returns the Top 100 of the genre
+// with the given name. The token and caches are kept in the working
+// directory, like for NewClient.
+func FetchTop100(username, password, genre string) ([]Track, error) {
+	return FetchTop100Ctx(context.Background(), username, password, genre)
+}
+
+// FetchTop100Ctx is like FetchTop100 but uses ctx for its requests.
+func FetchTop100Ctx(ctx context.Context, username, password, genre string) ([]Track, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.fetchTop100(ctx, username, password, genre)
+}
+
+func (c *Client) fetchTop100(ctx context.Context, username, password, genre string) ([]Track, error) {
+	if err := c.AuthenticateCtx(ctx, username, password); err != nil {
+		return nil, err
+	}
+	return c.GetTop100ByNameCtx(ctx, genre)
+}
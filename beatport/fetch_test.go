@@ -0,0 +1,29 @@
+package beatport
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetchTop100(t *testing.T) {
+	dir := t.TempDir()
+
+	client, _ := NewClient()
+	client.Offline = true
+	client.TokenPath = filepath.Join(dir, TokenFile)
+	client.ClientIDPath = filepath.Join(dir, ClientIDFile)
+	client.GenresPath = filepath.Join(dir, GenresFile)
+	client.RateLimit = 0
+
+	tracks, err := client.fetchTop100(context.Background(), "anyone", "anything", "House")
+	if err != nil {
+		t.Fatalf("fetchTop100 failed: %v", err)
+	}
+	if len(tracks) != 10 || tracks[0].Rank != 1 {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+	if !client.TokenValid() {
+		t.Errorf("Expected the client to be authenticated")
+	}
+}
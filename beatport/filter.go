@@ -0,0 +1,99 @@
+package beatport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterByBPM returns the tracks with a BPM between minBPM and maxBPM,
+// inclusive, in their original order. A minBPM or maxBPM of zero leaves that
+// end of the range open. When a range is set, tracks with an unknown BPM are
+// left out.
+func FilterByBPM(tracks []Track, minBPM, maxBPM int) []Track {
+	if minBPM <= 0 && maxBPM <= 0 {
+		return tracks
+	}
+	filtered := make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		if track.BPM <= 0 || (minBPM > 0 && track.BPM < minBPM) || (maxBPM > 0 && track.BPM > maxBPM) {
+			continue
+		}
+		filtered = append(filtered, track)
+	}
+	return filtered
+}
+
+// FilterSince returns the tracks released on or after the day of since, in
+// their original order. Tracks without a release date that can be parsed
+// are left out.
+func FilterSince(tracks []Track, since time.Time) []Track {
+	day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+	filtered := make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		if released, err := track.ReleaseTime(); err == nil && !released.Before(day) {
+			filtered = append(filtered, track)
+		}
+	}
+	return filtered
+}
+
+// ParseCamelot parses a key in Camelot notation, e.g. "8A" or "12b", into a
+// Key with only its Camelot position set.
+func ParseCamelot(s string) (*Key, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return nil, fmt.Errorf("invalid Camelot key %q", s)
+	}
+	number, err := strconv.Atoi(s[:len(s)-1])
+	letter := s[len(s)-1:]
+	if err != nil || number < 1 || number > 12 || (letter != "A" && letter != "B") {
+		return nil, fmt.Errorf("invalid Camelot key %q, expected 1A to 12B", s)
+	}
+	return &Key{CamelotNumber: number, CamelotLetter: letter}, nil
+}
+
+// HarmonicallyCompatible reports whether two keys in Camelot notation can be
+// mixed harmonically: they are the same key, neighbours on the wheel with the
+// same letter (e.g. 8A and 9A, or 12A and 1A), or relative major and minor
+// (8A and 8B). Invalid keys are compatible with nothing.
+func HarmonicallyCompatible(a, b string) bool {
+	ka, err := ParseCamelot(a)
+	if err != nil {
+		return false
+	}
+	kb, err := ParseCamelot(b)
+	if err != nil {
+		return false
+	}
+	if ka.CamelotLetter != kb.CamelotLetter {
+		return ka.CamelotNumber == kb.CamelotNumber
+	}
+	switch (ka.CamelotNumber - kb.CamelotNumber + 12) % 12 {
+	case 0, 1, 11:
+		return true
+	}
+	return false
+}
+
+// FilterByKey returns the tracks in the key given in Camelot notation, in
+// their original order. With compatible, tracks in keys that are
+// HarmonicallyCompatible with it are kept too. Tracks with an unknown key
+// are left out, and an invalid key matches no tracks.
+func FilterByKey(tracks []Track, camelot string, compatible bool) []Track {
+	filtered := make([]Track, 0, len(tracks))
+	want, err := ParseCamelot(camelot)
+	if err != nil {
+		return filtered
+	}
+	for _, track := range tracks {
+		if track.Key.Camelot() == "" {
+			continue
+		}
+		if CompareKeys(want, track.Key) == 0 || compatible && HarmonicallyCompatible(camelot, track.Key.Camelot()) {
+			filtered = append(filtered, track)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,96 @@
+package beatport
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func trackIDs(tracks []Track) []int {
+	ids := make([]int, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	return ids
+}
+
+func TestFilterByBPM(t *testing.T) {
+	tracks := []Track{{ID: 1, BPM: 122}, {ID: 2, BPM: 124}, {ID: 3}, {ID: 4, BPM: 128}, {ID: 5, BPM: 130}}
+
+	tests := []struct {
+		min, max int
+		want     []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{124, 128, []int{2, 4}},
+		{125, 0, []int{4, 5}},
+		{0, 124, []int{1, 2}},
+		{131, 0, []int{}},
+	}
+	for _, tt := range tests {
+		if got := trackIDs(FilterByBPM(tracks, tt.min, tt.max)); !slices.Equal(got, tt.want) {
+			t.Errorf("FilterByBPM(%d, %d) = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestHarmonicallyCompatible(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"8A", "8A", true},
+		{"8A", "9A", true},
+		{"8A", "7A", true},
+		{"8A", "8B", true},
+		{"12B", "1B", true},
+		{"1a", "12A", true},
+		{"8A", "10A", false},
+		{"8A", "9B", false},
+		{"8A", "2A", false},
+		{"8A", "", false},
+		{"13A", "12A", false},
+		{"8C", "8A", false},
+	}
+	for _, tt := range tests {
+		if got := HarmonicallyCompatible(tt.a, tt.b); got != tt.want {
+			t.Errorf("HarmonicallyCompatible(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFilterByKey(t *testing.T) {
+	tracks := []Track{
+		{ID: 1, Key: &Key{CamelotNumber: 8, CamelotLetter: "A"}},
+		{ID: 2, Key: &Key{CamelotNumber: 9, CamelotLetter: "A"}},
+		{ID: 3},
+		{ID: 4, Key: &Key{CamelotNumber: 8, CamelotLetter: "B"}},
+		{ID: 5, Key: &Key{CamelotNumber: 3, CamelotLetter: "A"}},
+	}
+
+	if got, want := trackIDs(FilterByKey(tracks, "8a", false)), []int{1}; !slices.Equal(got, want) {
+		t.Errorf("FilterByKey(8a) = %v, want %v", got, want)
+	}
+	if got, want := trackIDs(FilterByKey(tracks, "8A", true)), []int{1, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("FilterByKey(8A, compatible) = %v, want %v", got, want)
+	}
+	if got := FilterByKey(tracks, "8X", true); len(got) != 0 {
+		t.Errorf("Expected no tracks for an invalid key, got %v", trackIDs(got))
+	}
+}
+
+func TestFilterSince(t *testing.T) {
+	tracks := []Track{
+		{ID: 1, ReleaseDate: "2024-04-30"},
+		{ID: 2, ReleaseDate: "2024-05-01"},
+		{ID: 3},
+		{ID: 4, ReleaseDate: "May 2024"},
+		{ID: 5, ReleaseDate: "2024-06-15"},
+	}
+
+	// The time of day doesn't matter, only the date
+	since := time.Date(2024, time.May, 1, 18, 30, 0, 0, time.UTC)
+	if got, want := trackIDs(FilterSince(tracks, since)), []int{2, 5}; !slices.Equal(got, want) {
+		t.Errorf("FilterSince = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// labelTracksPageSize is how many of a label's tracks GetLabelTracks fetches,
+// newest first.
+const labelTracksPageSize = 100
+
+// GetLabel returns the details of a label, including the slug and image that
+// are usually left out when the label is embedded in a track.
+func (c *Client) GetLabel(id int) (*Label, error) {
+	return c.GetLabelCtx(context.Background(), id)
+}
+
+// GetLabelCtx is like GetLabel but uses ctx for its requests.
+func (c *Client) GetLabelCtx(ctx context.Context, id int) (*Label, error) {
+	url := fmt.Sprintf("%s/catalog/labels/%d/", c.BaseURL, id)
+	var label Label
+	if err := c.getJSON(ctx, url, fmt.Sprintf("get label %d", id), &label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// GetLabelTracks returns the most recently released tracks of a label. Like
+// GetTracksPaginated, it returns the tracks fetched before a failing page
+// along with the error.
+func (c *Client) GetLabelTracks(id int) ([]Track, error) {
+	return c.GetLabelTracksCtx(context.Background(), id)
+}
+
+// GetLabelTracksCtx is like GetLabelTracks but uses ctx for its requests.
+func (c *Client) GetLabelTracksCtx(ctx context.Context, id int) (_ []Track, err error) {
+	defer c.observeFetch("label_tracks", time.Now(), &err)
+
+	startURL := fmt.Sprintf("%s/catalog/labels/%d/tracks/?per_page=%d&order_by=-publish_date", c.BaseURL, id, c.perPage(labelTracksPageSize))
+	return c.fetchTrackPages(ctx, startURL, labelTracksPageSize)
+}
@@ -0,0 +1,60 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/labels/17/" {
+			t.Errorf("Expected path /catalog/labels/17/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 17, "name": "Drumcode", "slug": "drumcode", "image": {"id": 9, "uri": "https://geo-media.beatport.com/image_size/500x500/9.jpg"}}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	label, err := client.GetLabel(17)
+	if err != nil {
+		t.Fatalf("GetLabel failed: %v", err)
+	}
+	if label.Name != "Drumcode" || label.ImageURL != "https://geo-media.beatport.com/image_size/500x500/9.jpg" {
+		t.Errorf("Unexpected label: %+v", label)
+	}
+	if got, want := label.URL(), "https://www.beatport.com/label/drumcode/17"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+}
+
+func TestGetLabelTracks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/labels/17/tracks/" {
+			t.Errorf("Expected path /catalog/labels/17/tracks/, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("order_by"); got != "-publish_date" {
+			t.Errorf("Expected order_by=-publish_date, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"count": 2, "results": [{"id": 1, "name": "Newest"}, {"id": 2, "name": "Older"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.GetLabelTracks(17)
+	if err != nil {
+		t.Fatalf("GetLabelTracks failed: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Name != "Newest" {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+}
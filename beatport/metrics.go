@@ -0,0 +1,70 @@
+package beatport
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Metrics receives measurements of the client's activity, e.g. to export them
+// as Prometheus counters and histograms. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	// ObserveRequest is called after every HTTP attempt, including retries.
+	// endpoint is the request path with numeric IDs replaced by ":id", and
+	// status is zero if no response was received.
+	ObserveRequest(method, endpoint string, status int, duration time.Duration)
+
+	// IncRetry is called every time a request is retried.
+	IncRetry(method, endpoint string)
+
+	// IncAuthFailure is called when authenticating or refreshing the token
+	// fails. op is the failed step, e.g. "login" or "token refresh".
+	IncAuthFailure(op string)
+
+	// ObserveFetch is called when a fetch method such as GetGenreChart
+	// returns, with the error it returned.
+	ObserveFetch(op string, duration time.Duration, err error)
+}
+
+// NopMetrics is a Metrics that discards everything. It is the default.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveRequest(method, endpoint string, status int, duration time.Duration) {}
+func (NopMetrics) IncRetry(method, endpoint string)                                           {}
+func (NopMetrics) IncAuthFailure(op string)                                                   {}
+func (NopMetrics) ObserveFetch(op string, duration time.Duration, err error)                  {}
+
+// metrics returns the configured metrics, discarding them if there are none.
+func (c *Client) metrics() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics{}
+	}
+	return c.Metrics
+}
+
+// observeFetch reports a fetch that started at start and failed with *err,
+// if not nil. It is meant to be deferred.
+func (c *Client) observeFetch(op string, start time.Time, err *error) {
+	c.metrics().ObserveFetch(op, time.Since(start), *err)
+}
+
+// observeAuthFailure reports a failed authentication step, taken from err if
+// it is an AuthError.
+func (c *Client) observeAuthFailure(op string, err error) {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		op = authErr.Op
+	}
+	c.metrics().IncAuthFailure(op)
+}
+
+var reNumericSegment = regexp.MustCompile(`/\d+(/|$)`)
+
+// endpointLabel returns path with its numeric IDs replaced by ":id", so
+// requests for different genres or charts share a label.
+func endpointLabel(path string) string {
+	// Replace twice, as adjacent IDs share the slash between them
+	path = reNumericSegment.ReplaceAllString(path, "/:id$1")
+	return reNumericSegment.ReplaceAllString(path, "/:id$1")
+}
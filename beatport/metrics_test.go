@@ -0,0 +1,101 @@
+package beatport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics records the calls it receives.
+type recordingMetrics struct {
+	mu           sync.Mutex
+	requests     []string
+	retries      int
+	authFailures []string
+	fetches      map[string]error
+}
+
+func (m *recordingMetrics) ObserveRequest(method, endpoint string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", method, endpoint, status))
+}
+
+func (m *recordingMetrics) IncRetry(method, endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *recordingMetrics) IncAuthFailure(op string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authFailures = append(m.authFailures, op)
+}
+
+func (m *recordingMetrics) ObserveFetch(op string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fetches == nil {
+		m.fetches = map[string]error{}
+	}
+	m.fetches[op] = err
+}
+
+func TestMetrics(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/catalog/genres/5/top/100":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"results": [{"id": 1}]}`)
+		case "/login/":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"detail": "Invalid credentials"}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.AuthURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+	client.RetryBaseDelay = time.Millisecond
+	client.Metrics = metrics
+
+	if _, err := client.GetTop100(5); err != nil {
+		t.Fatalf("GetTop100 failed: %v", err)
+	}
+	want := []string{"GET /catalog/genres/:id/top/:id 503", "GET /catalog/genres/:id/top/:id 200"}
+	if fmt.Sprint(metrics.requests) != fmt.Sprint(want) {
+		t.Errorf("Expected requests %q, got %q", want, metrics.requests)
+	}
+	if metrics.retries != 1 {
+		t.Errorf("Expected 1 retry, got %d", metrics.retries)
+	}
+	if err, ok := metrics.fetches["genre_chart"]; !ok || err != nil {
+		t.Errorf("Expected a successful genre_chart fetch, got %v (observed: %v)", err, ok)
+	}
+
+	client.Token = nil
+	if err := client.Authenticate("user", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if len(metrics.authFailures) != 1 || metrics.authFailures[0] != "login" {
+		t.Errorf("Expected a login failure, got %q", metrics.authFailures)
+	}
+}
@@ -1,11 +1,36 @@
 package beatport
 
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type OAuthToken struct {
-	AccessToken  string `json:"access_token"`
-	ExpiresIn    int    `json:"expires_in"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
-	Scope        string `json:"scope"`
+	AccessToken  string    `json:"access_token"`
+	ExpiresIn    int       `json:"expires_in"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	Scope        string    `json:"scope"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+
+	// IssuedAt is when the user logged in for the token. Refreshing the token
+	// keeps it, so it tells how long ago the credentials were last entered.
+	IssuedAt time.Time `json:"issued_at,omitzero"`
+
+	// ClientID is the API client ID the token was issued to. It is saved with
+	// the token so refreshing it doesn't have to scrape the ID again.
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// HasScope reports whether scope is one of the space-separated scopes the
+// token was granted.
+func (t *OAuthToken) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(t.Scope), scope)
 }
 
 type Genre struct {
@@ -14,23 +39,379 @@ type Genre struct {
 	Slug string `json:"slug"`
 }
 
+// WebsiteURL is the base URL of the public Beatport website.
+const WebsiteURL = "https://www.beatport.com"
+
 type Artist struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 	Slug string `json:"slug"`
+
+	// Image and Bio are only filled in by GetArtist.
+	Image *Image `json:"image,omitempty"`
+	Bio   string `json:"bio,omitempty"`
+}
+
+// URL returns the artist's profile page on the Beatport website, or an empty
+// string if the artist's slug or ID is unknown.
+func (a Artist) URL() string {
+	return websiteURL("artist", a.Slug, a.ID)
+}
+
+// MarshalJSON encodes the artist with its URL.
+func (a Artist) MarshalJSON() ([]byte, error) {
+	type plainArtist Artist
+	return json.Marshal(struct {
+		plainArtist
+		URL string `json:"url,omitempty"`
+	}{plainArtist(a), a.URL()})
+}
+
+func websiteURL(kind, slug string, id int) string {
+	if slug == "" || id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%d", WebsiteURL, kind, slug, id)
+}
+
+// Image is an image hosted by Beatport. DynamicURI is a template with
+// {w}x{h} placeholders for requesting a specific size.
+type Image struct {
+	ID         int    `json:"id"`
+	URI        string `json:"uri"`
+	DynamicURI string `json:"dynamic_uri,omitempty"`
+}
+
+// DefaultImageSize is the width and height, in pixels, that images are
+// requested in when Beatport can resize them.
+const DefaultImageSize = 500
+
+// SizedURL returns the URL of the image resized to width x height, or its
+// original URL if Beatport can't resize it.
+func (i Image) SizedURL(width, height int) string {
+	uri := i.DynamicURI
+	if uri == "" {
+		uri = i.URI
+	}
+	return strings.NewReplacer("{w}", strconv.Itoa(width), "{h}", strconv.Itoa(height)).Replace(uri)
+}
+
+// Key is the musical key of a track, with its Camelot wheel position.
+type Key struct {
+	Name          string `json:"name"`
+	CamelotNumber int    `json:"camelot_number"`
+	CamelotLetter string `json:"camelot_letter"`
+}
+
+// Camelot returns the key in Camelot notation (e.g. "8A"), or an empty string
+// if the position is unknown.
+func (k *Key) Camelot() string {
+	if k == nil || k.CamelotNumber == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%s", k.CamelotNumber, k.CamelotLetter)
+}
+
+// CompareKeys orders keys by their position on the Camelot wheel: 1A, 1B,
+// 2A, ..., 12B. It returns a negative number if a comes first, a positive
+// number if b does and zero if they are in the same position. Keys with an
+// unknown position come last.
+func CompareKeys(a, b *Key) int {
+	aKnown, bKnown := a != nil && a.CamelotNumber != 0, b != nil && b.CamelotNumber != 0
+	switch {
+	case !aKnown && !bKnown:
+		return 0
+	case !aKnown:
+		return 1
+	case !bKnown:
+		return -1
+	}
+	if n := cmp.Compare(a.CamelotNumber, b.CamelotNumber); n != 0 {
+		return n
+	}
+	return strings.Compare(strings.ToUpper(a.CamelotLetter), strings.ToUpper(b.CamelotLetter))
+}
+
+// Price is the price of a track. Value is a decimal amount in the currency
+// given by Code, e.g. 1.49 USD.
+type Price struct {
+	Value   float64 `json:"value"`
+	Code    string  `json:"code"`
+	Display string  `json:"display,omitempty"`
+}
+
+type Label struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+
+	// ImageURL is the label's logo. It is usually only filled in by GetLabel.
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// UnmarshalJSON decodes a label, lifting the image URL out of the nested
+// image object the API returns.
+func (l *Label) UnmarshalJSON(data []byte) error {
+	type plainLabel Label
+	aux := struct {
+		*plainLabel
+		Image *Image `json:"image"`
+	}{plainLabel: (*plainLabel)(l)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Image != nil && l.ImageURL == "" {
+		l.ImageURL = aux.Image.URI
+	}
+	return nil
+}
+
+// URL returns the label's page on the Beatport website, or an empty string if
+// the label's slug or ID is unknown.
+func (l Label) URL() string {
+	return websiteURL("label", l.Slug, l.ID)
 }
 
 type Track struct {
-	ID      int      `json:"id"`
-	Name    string   `json:"name"`
-	Artists []Artist `json:"artists"`
-	MixName string   `json:"mix_name"`
+	// Rank is the 1-based position of the track on the chart it was fetched
+	// from. It is zero for tracks that didn't come from a chart.
+	Rank int `json:"rank,omitempty"`
+
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	Artists     []Artist `json:"artists"`
+	Remixers    []Artist `json:"remixers"`
+	MixName     string   `json:"mix_name"`
+	BPM         int      `json:"bpm"`
+	Key         *Key     `json:"key"`
+	ReleaseDate string   `json:"publish_date"`
+	PreviewURL  string   `json:"sample_url"`
+	Length      string   `json:"length"`
+	LengthMs    int      `json:"length_ms"`
+	Price       *Price   `json:"price,omitempty"`
+
+	// ISRC is the International Standard Recording Code of the track. It is
+	// empty for tracks Beatport has none for.
+	ISRC string `json:"isrc"`
+
+	// Label, CatalogNumber and ArtworkURL come from the track's release and
+	// may be missing, e.g. for pre-release tracks. ArtworkURL is the cover
+	// image at DefaultImageSize.
+	Label         *Label `json:"label,omitempty"`
+	CatalogNumber string `json:"catalog_number,omitempty"`
+	ArtworkURL    string `json:"artwork_url,omitempty"`
+}
+
+// trackRelease is the part of the release embedded in a track payload that
+// is flattened onto Track.
+type trackRelease struct {
+	Label         *Label `json:"label"`
+	CatalogNumber string `json:"catalog_number"`
+	Image         *Image `json:"image"`
+}
+
+// UnmarshalJSON decodes a track, lifting the label, catalog number and
+// artwork out of the nested release object the API returns.
+func (t *Track) UnmarshalJSON(data []byte) error {
+	type plainTrack Track
+	aux := struct {
+		*plainTrack
+		Release *trackRelease `json:"release"`
+	}{plainTrack: (*plainTrack)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Release != nil {
+		if t.Label == nil {
+			t.Label = aux.Release.Label
+		}
+		if t.CatalogNumber == "" {
+			t.CatalogNumber = aux.Release.CatalogNumber
+		}
+		if t.ArtworkURL == "" && aux.Release.Image != nil {
+			t.ArtworkURL = aux.Release.Image.SizedURL(DefaultImageSize, DefaultImageSize)
+		}
+	}
+	return nil
+}
+
+// URL returns the track's page on the Beatport website, or an empty string if
+// the track's slug or ID is unknown.
+func (t Track) URL() string {
+	return websiteURL("track", t.Slug, t.ID)
+}
+
+// MarshalJSON encodes the track with its URL.
+func (t Track) MarshalJSON() ([]byte, error) {
+	type plainTrack Track
+	return json.Marshal(struct {
+		plainTrack
+		URL string `json:"url,omitempty"`
+	}{plainTrack(t), t.URL()})
+}
+
+// ArtistNames returns the names of all the track's artists joined with ", ".
+func (t Track) ArtistNames() string {
+	return artistNames(t.Artists)
+}
+
+// RemixerNames returns the names of all the track's remixers joined with
+// ", ".
+func (t Track) RemixerNames() string {
+	return artistNames(t.Remixers)
+}
+
+// MixTitle returns the mix name, prefixed with the remixers when the mix name
+// doesn't already mention them, e.g. "Dubfire Remix" for a mix called
+// "Remix" by Dubfire.
+func (t Track) MixTitle() string {
+	if len(t.Remixers) == 0 {
+		return t.MixName
+	}
+	mix := strings.ToLower(t.MixName)
+	for _, r := range t.Remixers {
+		if strings.Contains(mix, strings.ToLower(r.Name)) {
+			return t.MixName
+		}
+	}
+	if t.MixName == "" {
+		return t.RemixerNames() + " Remix"
+	}
+	return t.RemixerNames() + " " + t.MixName
+}
+
+// LabelName returns the name of the track's label, or an empty string if the
+// label is unknown.
+func (t Track) LabelName() string {
+	if t.Label == nil {
+		return ""
+	}
+	return t.Label.Name
+}
+
+// ReleaseDateLayout is the layout of the release dates of tracks and
+// releases, for time.Parse.
+const ReleaseDateLayout = "2006-01-02"
+
+// ReleaseTime returns the track's release date, at midnight UTC. It fails if
+// the date is missing or not in ReleaseDateLayout.
+func (t Track) ReleaseTime() (time.Time, error) {
+	return time.Parse(ReleaseDateLayout, t.ReleaseDate)
+}
+
+// Duration returns the length of the track.
+func (t Track) Duration() time.Duration {
+	return time.Duration(t.LengthMs) * time.Millisecond
+}
+
+// KeyName returns the name of the track's key, or an empty string if the key
+// is unknown.
+func (t Track) KeyName() string {
+	if t.Key == nil {
+		return ""
+	}
+	return t.Key.Name
+}
+
+// Release is an EP, album or single: a group of tracks released together on a
+// label.
+type Release struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Artists       []Artist `json:"artists"`
+	Label         *Label   `json:"label,omitempty"`
+	CatalogNumber string   `json:"catalog_number,omitempty"`
+	TrackCount    int      `json:"track_count"`
+	ReleaseDate   string   `json:"publish_date"`
+}
+
+// ArtistNames returns the names of all the release's artists joined with
+// ", ".
+func (r Release) ArtistNames() string {
+	return artistNames(r.Artists)
+}
+
+func artistNames(artists []Artist) string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// LabelName returns the name of the release's label, or an empty string if
+// the label is unknown.
+func (r Release) LabelName() string {
+	if r.Label == nil {
+		return ""
+	}
+	return r.Label.Name
+}
+
+// Chart is a DJ chart: a tracklist curated and published by a DJ.
+type Chart struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Artist   *Artist `json:"artist,omitempty"`
+	ImageURL string  `json:"image_url,omitempty"`
+}
+
+// UnmarshalJSON decodes a chart, lifting the image URL out of the nested
+// image object the API returns.
+func (c *Chart) UnmarshalJSON(data []byte) error {
+	type plainChart Chart
+	aux := struct {
+		*plainChart
+		Image *Image `json:"image"`
+	}{plainChart: (*plainChart)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Image != nil && c.ImageURL == "" {
+		c.ImageURL = aux.Image.URI
+	}
+	return nil
+}
+
+// ChartResult is a fetched chart together with the genre and time it was
+// fetched for, so saved charts are self-describing.
+type ChartResult struct {
+	Genre     Genre     `json:"genre"`
+	ChartType string    `json:"chart_type"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Tracks    []Track   `json:"tracks"`
 }
 
 type GenreResponse struct {
 	Results []Genre `json:"results"`
+	Next    string  `json:"next"`
+}
+
+// SearchResponse is a page of search results. Unlike the catalog endpoints,
+// search returns its tracks under "tracks".
+type SearchResponse struct {
+	Tracks []Track `json:"tracks"`
+}
+
+type ReleaseResponse struct {
+	Results []Release `json:"results"`
+	Next    string    `json:"next"`
+	Count   int       `json:"count"`
+}
+
+type ChartResponse struct {
+	Results []Chart `json:"results"`
+	Next    string  `json:"next"`
+	Count   int     `json:"count"`
 }
 
 type TrackResponse struct {
 	Results []Track `json:"results"`
+	Next    string  `json:"next"`
+	Count   int     `json:"count"`
 }
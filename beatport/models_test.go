@@ -0,0 +1,157 @@
+package beatport
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTrackDecodesMusicalMetadata(t *testing.T) {
+	var tracks []Track
+	data := `[
+		{"id": 1, "bpm": 128, "key": {"name": "A Minor", "camelot_number": 8, "camelot_letter": "A"}, "publish_date": "2024-05-10"},
+		{"id": 2, "bpm": 124, "key": null}
+	]`
+	if err := json.Unmarshal([]byte(data), &tracks); err != nil {
+		t.Fatalf("Failed to decode tracks: %v", err)
+	}
+
+	if tracks[0].BPM != 128 || tracks[0].KeyName() != "A Minor" || tracks[0].Key.Camelot() != "8A" || tracks[0].ReleaseDate != "2024-05-10" {
+		t.Errorf("Unexpected metadata: %+v", tracks[0])
+	}
+	if tracks[1].Key != nil || tracks[1].KeyName() != "" || tracks[1].Key.Camelot() != "" {
+		t.Errorf("Expected null key to decode as unknown: %+v", tracks[1])
+	}
+}
+
+func TestTrackDecodesReleaseLabel(t *testing.T) {
+	var tracks []Track
+	data := `[
+		{"id": 1, "release": {"id": 10, "catalog_number": "DC123", "label": {"id": 5, "name": "Drumcode"}}},
+		{"id": 2, "label": {"id": 6, "name": "Afterlife"}, "catalog_number": "AL001"},
+		{"id": 3}
+	]`
+	if err := json.Unmarshal([]byte(data), &tracks); err != nil {
+		t.Fatalf("Failed to decode tracks: %v", err)
+	}
+
+	if tracks[0].LabelName() != "Drumcode" || tracks[0].CatalogNumber != "DC123" {
+		t.Errorf("Expected label from release, got %+v", tracks[0])
+	}
+	if tracks[1].LabelName() != "Afterlife" || tracks[1].CatalogNumber != "AL001" {
+		t.Errorf("Expected top-level label, got %+v", tracks[1])
+	}
+	if tracks[2].Label != nil || tracks[2].LabelName() != "" || tracks[2].CatalogNumber != "" {
+		t.Errorf("Expected missing label to decode as unknown, got %+v", tracks[2])
+	}
+}
+
+func TestTrackDecodesLengthAndPrice(t *testing.T) {
+	var track Track
+	data := `{"id": 1, "length": "6:12", "length_ms": 372000, "price": {"code": "EUR", "symbol": "€", "value": 1.49, "display": "€1.49"}}`
+	if err := json.Unmarshal([]byte(data), &track); err != nil {
+		t.Fatalf("Failed to decode track: %v", err)
+	}
+
+	if track.Length != "6:12" || track.Duration() != 372*time.Second {
+		t.Errorf("Unexpected length: %q / %v", track.Length, track.Duration())
+	}
+	if track.Price == nil || track.Price.Value != 1.49 || track.Price.Code != "EUR" {
+		t.Errorf("Unexpected price: %+v", track.Price)
+	}
+}
+
+func TestTrackArtistNames(t *testing.T) {
+	track := Track{Artists: []Artist{{Name: "First"}, {Name: "Second"}, {Name: "Third"}}}
+	if got, want := track.ArtistNames(), "First, Second, Third"; got != want {
+		t.Errorf("ArtistNames() = %q, want %q", got, want)
+	}
+	if got := (Track{}).ArtistNames(); got != "" {
+		t.Errorf("ArtistNames() without artists = %q, want empty", got)
+	}
+}
+
+func TestTrackURL(t *testing.T) {
+	track := Track{ID: 900001, Slug: "night-drive", Artists: []Artist{{ID: 1001, Name: "Example Artist", Slug: "example-artist"}}}
+	if got, want := track.URL(), "https://www.beatport.com/track/night-drive/900001"; got != want {
+		t.Errorf("Track.URL() = %q, want %q", got, want)
+	}
+	if got, want := track.Artists[0].URL(), "https://www.beatport.com/artist/example-artist/1001"; got != want {
+		t.Errorf("Artist.URL() = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(track)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded struct {
+		URL     string `json:"url"`
+		Artists []struct {
+			URL string `json:"url"`
+		} `json:"artists"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.URL != track.URL() || len(decoded.Artists) != 1 || decoded.Artists[0].URL != track.Artists[0].URL() {
+		t.Errorf("JSON is missing the URLs: %s", data)
+	}
+
+	if (Track{ID: 1}).URL() != "" {
+		t.Errorf("Expected no URL without a slug")
+	}
+}
+
+func TestTrackMixTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		track Track
+		want  string
+	}{
+		{"no remixers", Track{MixName: "Original Mix"}, "Original Mix"},
+		{"remixer in mix name", Track{MixName: "Dubfire Remix", Remixers: []Artist{{Name: "Dubfire"}}}, "Dubfire Remix"},
+		{"generic mix name", Track{MixName: "Remix", Remixers: []Artist{{Name: "Dubfire"}}}, "Dubfire Remix"},
+		{"no mix name", Track{Remixers: []Artist{{Name: "A"}, {Name: "B"}}}, "A, B Remix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.track.MixTitle(); got != tt.want {
+				t.Errorf("MixTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageSizedURL(t *testing.T) {
+	img := Image{URI: "https://example.com/image_size/1400x1400/1.jpg", DynamicURI: "https://example.com/image_size/{w}x{h}/1.jpg"}
+	if got, want := img.SizedURL(250, 250), "https://example.com/image_size/250x250/1.jpg"; got != want {
+		t.Errorf("SizedURL() = %q, want %q", got, want)
+	}
+	img.DynamicURI = ""
+	if got := img.SizedURL(250, 250); got != img.URI {
+		t.Errorf("SizedURL() without a dynamic URI = %q, want %q", got, img.URI)
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	keys := []*Key{
+		{CamelotNumber: 12, CamelotLetter: "B"},
+		nil,
+		{CamelotNumber: 2, CamelotLetter: "A"},
+		{CamelotNumber: 1, CamelotLetter: "B"},
+		{Name: "Unknown"},
+		{CamelotNumber: 10, CamelotLetter: "A"},
+		{CamelotNumber: 1, CamelotLetter: "A"},
+	}
+	slices.SortStableFunc(keys, CompareKeys)
+
+	var got []string
+	for _, k := range keys {
+		got = append(got, k.Camelot())
+	}
+	want := []string{"1A", "1B", "2A", "10A", "12B", "", ""}
+	if !slices.Equal(got, want) {
+		t.Errorf("Sorted keys = %q, want %q", got, want)
+	}
+}
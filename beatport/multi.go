@@ -0,0 +1,111 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// multiFetchWorkers bounds the number of charts fetched concurrently.
+const multiFetchWorkers = 4
+
+// GenreErrors maps genre IDs to the error that occurred fetching their chart.
+type GenreErrors map[int]error
+
+func (e GenreErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("genre %d: %v", id, e[id]))
+	}
+	return fmt.Sprintf("failed to fetch %d genre(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the genres, ordered by genre ID, so
+// errors.Is matches e.g. ErrRateLimited if any genre failed with it.
+func (e GenreErrors) Unwrap() []error {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		errs[i] = e[id]
+	}
+	return errs
+}
+
+// GetTop100Multi fetches the Top 100 of several genres concurrently. Charts
+// that could be fetched, even partly, are returned even if others failed;
+// the failures are reported per genre in a GenreErrors.
+func (c *Client) GetTop100Multi(genreIDs []int) (map[int][]Track, error) {
+	return c.GetTop100MultiCtx(context.Background(), genreIDs)
+}
+
+// GetTop100MultiCtx is like GetTop100Multi but uses ctx for its requests.
+func (c *Client) GetTop100MultiCtx(ctx context.Context, genreIDs []int) (map[int][]Track, error) {
+	return c.GetGenreChartMultiCtx(ctx, genreIDs, ChartTop100, 0)
+}
+
+// GetGenreChartMulti is like GetTop100Multi, but fetches the top limit
+// tracks of the given chart type, like GetGenreChart.
+func (c *Client) GetGenreChartMulti(genreIDs []int, chartType string, limit int) (map[int][]Track, error) {
+	return c.GetGenreChartMultiCtx(context.Background(), genreIDs, chartType, limit)
+}
+
+// GetGenreChartMultiCtx is like GetGenreChartMulti but uses ctx for its
+// requests.
+func (c *Client) GetGenreChartMultiCtx(ctx context.Context, genreIDs []int, chartType string, limit int) (map[int][]Track, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[int][]Track, len(genreIDs))
+		errs    = make(GenreErrors)
+		jobs    = make(chan int)
+	)
+
+	// Report the genres done rather than the pages of every genre
+	ctx = c.withProgress(ctx)
+	genres := progressFrom(ctx)
+	genres.setTotal(len(genreIDs))
+	ctx = withoutProgress(ctx)
+
+	for i := 0; i < min(multiFetchWorkers, len(genreIDs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for genreID := range jobs {
+				tracks, err := c.GetGenreChartCtx(ctx, genreID, chartType, limit)
+				mu.Lock()
+				if err != nil {
+					errs[genreID] = err
+				}
+				if err == nil || len(tracks) > 0 {
+					results[genreID] = tracks
+				}
+				mu.Unlock()
+				genres.add(1)
+			}
+		}()
+	}
+
+	for _, genreID := range genreIDs {
+		jobs <- genreID
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
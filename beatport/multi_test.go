@@ -0,0 +1,68 @@
+package beatport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTop100Multi(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/catalog/genres/1/top/100":
+			fmt.Fprint(w, `{"results": [{"id": 101, "name": "Techno Track"}]}`)
+		case "/catalog/genres/2/top/100":
+			fmt.Fprint(w, `{"results": [{"id": 201, "name": "House Track"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+
+	results, err := client.GetTop100Multi([]int{1, 2, 3})
+
+	var genreErrs GenreErrors
+	if !errors.As(err, &genreErrs) || len(genreErrs) != 1 || genreErrs[3] == nil {
+		t.Fatalf("Expected a single error for genre 3, got %v", err)
+	}
+	if len(results) != 2 || results[1][0].Name != "Techno Track" || results[2][0].Name != "House Track" {
+		t.Errorf("Unexpected results: %v", results)
+	}
+}
+
+func TestGetGenreChartMulti(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chart_type") != "hype" || r.URL.Query().Get("per_page") != "10" {
+			t.Errorf("Expected the first 10 tracks of the hype chart, got %s", r.URL)
+		}
+		fmt.Fprintf(w, `{"results": [{"id": 1, "name": "Hype Track %s"}]}`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+
+	results, err := client.GetGenreChartMulti([]int{1, 2}, ChartHype100, 10)
+	if err != nil {
+		t.Fatalf("GetGenreChartMulti failed: %v", err)
+	}
+	if len(results) != 2 || results[2][0].Name != "Hype Track /catalog/genres/2/top/100" {
+		t.Errorf("Unexpected results: %v", results)
+	}
+}
+
+func TestGenreErrorsUnwrap(t *testing.T) {
+	err := error(GenreErrors{1: errors.New("not found"), 2: fmt.Errorf("fetch: %w", ErrRateLimited)})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected %v to match ErrRateLimited", err)
+	}
+}
@@ -0,0 +1,30 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// newReleasesLimit is how many tracks GetNewReleases returns when no limit
+// is given, as the feed goes back through the whole catalog.
+const newReleasesLimit = 100
+
+// GetNewReleases returns the most recently released tracks of a genre,
+// newest first. A limit of zero or less returns the newest 100. Like
+// GetTracksPaginated, it returns the tracks fetched before a failing page
+// along with the error.
+func (c *Client) GetNewReleases(genreID, limit int) ([]Track, error) {
+	return c.GetNewReleasesCtx(context.Background(), genreID, limit)
+}
+
+// GetNewReleasesCtx is like GetNewReleases but uses ctx for its requests.
+func (c *Client) GetNewReleasesCtx(ctx context.Context, genreID, limit int) (_ []Track, err error) {
+	defer c.observeFetch("new_releases", time.Now(), &err)
+
+	if limit <= 0 {
+		limit = newReleasesLimit
+	}
+	startURL := fmt.Sprintf("%s/catalog/genres/%d/tracks/?order_by=-publish_date&per_page=%d", c.BaseURL, genreID, c.perPage(limit))
+	return c.fetchTrackPages(ctx, startURL, limit)
+}
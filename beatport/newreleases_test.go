@@ -0,0 +1,42 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetNewReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/genres/6/tracks/" || r.URL.Query().Get("order_by") != "-publish_date" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"results": [{"id": 3, "publish_date": "2026-10-16"}, {"id": 2, "publish_date": "2026-10-15"}], "next": "%s/catalog/genres/6/tracks/?order_by=-publish_date&per_page=2&page=2", "count": 500}`, "http://"+r.Host)
+		case "2":
+			fmt.Fprint(w, `{"results": [{"id": 1, "publish_date": "2026-10-14"}, {"id": 0, "publish_date": "2026-10-13"}], "count": 500}`)
+		default:
+			t.Errorf("Expected only the pages up to the limit, got %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+	client.PageSize = 2
+
+	tracks, err := client.GetNewReleases(6, 3)
+	if err != nil {
+		t.Fatalf("GetNewReleases failed: %v", err)
+	}
+	if len(tracks) != 3 || tracks[0].ID != 3 || tracks[2].ID != 1 {
+		t.Errorf("Expected the 3 newest tracks, got %+v", tracks)
+	}
+	if tracks[0].Rank != 0 {
+		t.Errorf("Expected new releases to have no chart rank, got %d", tracks[0].Rank)
+	}
+}
@@ -0,0 +1,100 @@
+package beatport
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+var (
+	reOfflineSubGenres = regexp.MustCompile(`/catalog/genres/\d+/sub-genres/?$`)
+	reOfflineChart     = regexp.MustCompile(`/catalog/genres/\d+/top/100/?$`)
+	reOfflineTracks    = regexp.MustCompile(`/catalog/genres/\d+/tracks/?$`)
+)
+
+// serveOffline answers a request from the built-in fixtures instead of the
+// network. It covers the whole login flow, so an Offline client runs through
+// the same code paths as a real one.
+func (c *Client) serveOffline(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, "/docs/"):
+		return offlineResponse(req, http.StatusOK, `<script src="/static/btprt/offline.js"></script>`), nil
+	case strings.Contains(path, "/static/btprt/"):
+		return offlineResponse(req, http.StatusOK, `API_CLIENT_ID: 'offline'`), nil
+	case strings.HasSuffix(path, "/login/"):
+		return offlineResponse(req, http.StatusOK, `{"username": "offline"}`), nil
+	case strings.HasSuffix(path, "/o/authorize/"):
+		resp := offlineResponse(req, http.StatusFound, "")
+		resp.Header.Set("Location", c.AuthURL+"/o/post-message/?code=offline")
+		return resp, nil
+	case strings.HasSuffix(path, "/o/token/"):
+		return offlineResponse(req, http.StatusOK, `{"access_token": "offline", "refresh_token": "offline", "expires_in": 36000, "token_type": "Bearer"}`), nil
+	case strings.HasSuffix(path, "/my/account/"):
+		return offlineResponse(req, http.StatusOK, `{"id": 1, "username": "offline"}`), nil
+	case reOfflineSubGenres.MatchString(path):
+		return offlineResponse(req, http.StatusOK, `{"results": []}`), nil
+	case reOfflineChart.MatchString(path) && req.URL.Query().Get("type") == "releases":
+		return offlineFixture(req, "fixtures/releases.json")
+	case reOfflineChart.MatchString(path), reOfflineTracks.MatchString(path):
+		return offlineFixture(req, "fixtures/top100.json")
+	case strings.HasSuffix(path, "/catalog/genres/"):
+		return offlineFixture(req, "fixtures/genres.json")
+	case strings.HasSuffix(path, "/catalog/search"):
+		return offlineSearch(req)
+	}
+	return offlineResponse(req, http.StatusNotFound, fmt.Sprintf(`{"detail": "%s is not available offline"}`, path)), nil
+}
+
+func offlineFixture(req *http.Request, name string) (*http.Response, error) {
+	data, err := fixtures.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return offlineResponse(req, http.StatusOK, string(data)), nil
+}
+
+// offlineSearch answers a track search with the fixture tracks whose name or
+// artists contain the query.
+func offlineSearch(req *http.Request) (*http.Response, error) {
+	data, err := fixtures.ReadFile("fixtures/top100.json")
+	if err != nil {
+		return nil, err
+	}
+	var chart TrackResponse
+	if err := json.Unmarshal(data, &chart); err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(req.URL.Query().Get("q"))
+	result := SearchResponse{Tracks: []Track{}}
+	for _, track := range chart.Results {
+		if strings.Contains(strings.ToLower(track.Name+" "+track.ArtistNames()), query) {
+			result.Tracks = append(result.Tracks, track)
+		}
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return offlineResponse(req, http.StatusOK, string(body)), nil
+}
+
+func offlineResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
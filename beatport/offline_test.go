@@ -0,0 +1,43 @@
+package beatport
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOfflineClient(t *testing.T) {
+	dir := t.TempDir()
+
+	client, _ := NewClient()
+	client.Offline = true
+	client.BaseURL = "http://offline.invalid/v4"
+	client.AuthURL = "http://offline.invalid/v4/auth"
+	client.TokenPath = filepath.Join(dir, TokenFile)
+	client.ClientIDPath = filepath.Join(dir, ClientIDFile)
+	client.GenresPath = filepath.Join(dir, GenresFile)
+	client.RateLimit = 0
+
+	if err := client.Login("anyone", "anything"); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	code, err := client.Authorize()
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if err := client.GetToken(code); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	genre, err := client.ResolveGenre("tech house")
+	if err != nil {
+		t.Fatalf("ResolveGenre failed: %v", err)
+	}
+
+	tracks, err := client.GetTop100(genre.ID)
+	if err != nil {
+		t.Fatalf("GetTop100 failed: %v", err)
+	}
+	if len(tracks) != 10 || tracks[0].BPM == 0 || tracks[0].LabelName() == "" {
+		t.Errorf("Unexpected fixture tracks: %+v", tracks)
+	}
+}
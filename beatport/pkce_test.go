@@ -0,0 +1,115 @@
+package beatport
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// The example from RFC 7636, appendix B
+	got := codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("codeChallenge() = %q, want %q", got, want)
+	}
+}
+
+func TestAuthorizePKCE(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var challenge, verifier string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/o/authorize/":
+			if method := r.URL.Query().Get("code_challenge_method"); method != "S256" {
+				t.Errorf("Expected code_challenge_method S256, got %q", method)
+			}
+			challenge = r.URL.Query().Get("code_challenge")
+			w.Header().Set("Location", "/o/post-message/?code=test-code")
+			w.WriteHeader(http.StatusFound)
+		case "/o/token/":
+			verifier = r.PostFormValue("code_verifier")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+
+	code, err := client.Authorize()
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if err := client.GetToken(code); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	if len(verifier) != 43 {
+		t.Errorf("Expected a 43 character code verifier, got %q", verifier)
+	}
+	if challenge == "" || codeChallenge(verifier) != challenge {
+		t.Errorf("Code challenge %q doesn't match verifier %q", challenge, verifier)
+	}
+}
+
+func TestAuthorizeScopes(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var scope string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/o/authorize/":
+			scope = r.URL.Query().Get("scope")
+			w.Header().Set("Location", "/o/post-message/?code=test-code")
+			w.WriteHeader(http.StatusFound)
+		case "/o/token/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600, "scope": "app:locker"}`)
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client, _ := NewClient()
+	client.AuthURL = server.URL
+	client.ClientID = "test-client-id"
+	client.Scopes = []string{"app:locker", "user:dj"}
+	client.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	code, err := client.Authorize()
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if err := client.GetToken(code); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if scope != "app:locker user:dj" {
+		t.Errorf("Expected the scopes to be requested, got %q", scope)
+	}
+	if !client.Token.HasScope("app:locker") || client.Token.HasScope("user:dj") {
+		t.Errorf("Unexpected granted scopes %q", client.Token.Scope)
+	}
+	if !strings.Contains(logs.String(), "scope=user:dj") {
+		t.Errorf("Expected a warning about the missing scope, got %q", logs.String())
+	}
+
+	// Without Scopes, a new token asks for the scopes of the saved one
+	client.Scopes = nil
+	client.Token.ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := client.Authorize(); err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if scope != "app:locker" {
+		t.Errorf("Expected the saved token's scope to be requested, got %q", scope)
+	}
+}
@@ -0,0 +1,68 @@
+package beatport
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressFunc is called as a fetch that takes several requests progresses,
+// with the number of steps done and the estimated total, which is zero while
+// it is unknown. The steps are the pages of a paginated fetch, or the genres
+// of a multi-genre fetch.
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// progress counts the steps of a fetch for Client.Progress. Its methods do
+// nothing on a nil progress or one without a report function.
+type progress struct {
+	mu          sync.Mutex
+	done, total int
+	report      ProgressFunc
+}
+
+// withProgress returns ctx with a new progress reporting to Client.Progress,
+// unless ctx already has one, e.g. because the fetch is part of a
+// multi-genre fetch that reports by genre.
+func (c *Client) withProgress(ctx context.Context) context.Context {
+	if c.Progress == nil || ctx.Value(progressKey{}) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progress{report: c.Progress})
+}
+
+// withoutProgress returns ctx with a progress that reports nothing, so the
+// fetches made with it don't report their pages.
+func withoutProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progress{})
+}
+
+func progressFrom(ctx context.Context) *progress {
+	p, _ := ctx.Value(progressKey{}).(*progress)
+	return p
+}
+
+// setTotal sets the estimated total number of steps.
+func (p *progress) setTotal(total int) {
+	if p == nil || p.report == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = max(total, p.done)
+	p.report(p.done, p.total)
+}
+
+// add records that n more steps are done.
+func (p *progress) add(n int) {
+	if p == nil || p.report == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	if p.total > 0 {
+		p.total = max(p.total, p.done)
+	}
+	p.report(p.done, p.total)
+}
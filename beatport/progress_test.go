@@ -0,0 +1,64 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pagedTracksServer serves a chart of 9 tracks, 2 per page.
+func pagedTracksServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := 1
+		if page := r.URL.Query().Get("page"); page != "" {
+			n, _ = strconv.Atoi(page)
+		}
+		var results []string
+		for id := 2*n - 1; id <= min(2*n, 9); id++ {
+			results = append(results, fmt.Sprintf(`{"id": %d}`, id))
+		}
+		next := "null"
+		if n < 5 {
+			next = fmt.Sprintf(`"%s?per_page=2&page=%d"`, r.URL.Path, n+1)
+		}
+		fmt.Fprintf(w, `{"count": 9, "next": %s, "results": [%s]}`, next, strings.Join(results, ", "))
+	}))
+}
+
+func TestProgress(t *testing.T) {
+	server := pagedTracksServer(t)
+	defer server.Close()
+
+	var calls [][2]int
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+	client.Progress = func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}
+
+	if _, err := client.GetTracksPaginated(1, 0); err != nil {
+		t.Fatalf("GetTracksPaginated failed: %v", err)
+	}
+	if len(calls) == 0 || calls[len(calls)-1] != [2]int{5, 5} {
+		t.Errorf("Expected the progress to end at 5 of 5 pages, got %v", calls)
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i][0] < calls[i-1][0] {
+			t.Errorf("Progress went backwards: %v", calls)
+		}
+	}
+
+	// A multi-genre fetch reports the genres, not their pages
+	calls = nil
+	if _, err := client.GetTop100Multi([]int{1, 2, 3}); err != nil {
+		t.Fatalf("GetTop100Multi failed: %v", err)
+	}
+	if want := [][2]int{{0, 3}, {1, 3}, {2, 3}, {3, 3}}; fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("Progress = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,66 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxySchemes are the proxy URL schemes http.Transport can connect through.
+// socks5h resolves host names on the proxy instead of locally.
+var proxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ProxyTransport returns an HTTP transport that sends its requests through
+// the proxy at proxyURL, e.g. "http://proxy.example.com:3128" or
+// "socks5://localhost:1080". Without a proxyURL the proxy is taken from the
+// HTTP_PROXY and HTTPS_PROXY environment variables, with ALL_PROXY as the
+// fallback for both, and hosts listed in NO_PROXY are connected to directly.
+func ProxyTransport(proxyURL string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL == "" {
+		transport.Proxy = proxyFromEnvironment()
+		return transport, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if !slices.Contains(proxySchemes, u.Scheme) || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q, expected e.g. http://host:port or socks5://host:port", proxyURL)
+	}
+	transport.Proxy = http.ProxyURL(u)
+	return transport, nil
+}
+
+// proxyFromEnvironment returns a proxy function for the HTTP_PROXY,
+// HTTPS_PROXY, ALL_PROXY and NO_PROXY environment variables, or their
+// lowercase versions.
+func proxyFromEnvironment() func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if all := getenvAny("ALL_PROXY", "all_proxy"); all != "" {
+		if config.HTTPProxy == "" {
+			config.HTTPProxy = all
+		}
+		if config.HTTPSProxy == "" {
+			config.HTTPSProxy = all
+		}
+	}
+	proxyFunc := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+func getenvAny(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
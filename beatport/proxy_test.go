@@ -0,0 +1,73 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyTransport(t *testing.T) {
+	// A forward proxy receives the absolute URL of the requested resource
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.example.com" || r.URL.Path != "/catalog/genres/" {
+			t.Errorf("Expected a proxied request for api.example.com/catalog/genres/, got %s", r.URL)
+		}
+		fmt.Fprint(w, `{"results": [{"id": 1, "name": "Techno", "slug": "techno"}]}`)
+	}))
+	defer proxy.Close()
+
+	transport, err := ProxyTransport(proxy.URL)
+	if err != nil {
+		t.Fatalf("ProxyTransport failed: %v", err)
+	}
+	client, _ := NewClientWithHTTPClient(&http.Client{Transport: transport})
+	client.BaseURL = "http://api.example.com"
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	genres, err := client.GetGenres()
+	if err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+	if len(genres) != 1 || genres[0].Name != "Techno" {
+		t.Errorf("Unexpected genres: %v", genres)
+	}
+}
+
+func TestProxyTransportInvalidURL(t *testing.T) {
+	for _, proxyURL := range []string{"localhost:3128", "ftp://proxy.example.com", "http://"} {
+		if _, err := ProxyTransport(proxyURL); err == nil {
+			t.Errorf("ProxyTransport(%q): expected an error", proxyURL)
+		}
+	}
+	if _, err := ProxyTransport("socks5://localhost:1080"); err != nil {
+		t.Errorf("ProxyTransport(socks5) failed: %v", err)
+	}
+}
+
+func TestProxyFromEnvironment(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "http://https-proxy.example.com:3128")
+	t.Setenv("ALL_PROXY", "socks5://all-proxy.example.com:1080")
+	t.Setenv("NO_PROXY", "direct.example.com")
+
+	proxy := proxyFromEnvironment()
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.beatport.com/v4/", "http://https-proxy.example.com:3128"},
+		{"http://api.beatport.com/v4/", "socks5://all-proxy.example.com:1080"},
+		{"https://direct.example.com/", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		got, err := proxy(req)
+		if err != nil {
+			t.Fatalf("proxy(%s) failed: %v", tt.url, err)
+		}
+		if gotURL := fmt.Sprint(got); (got == nil && tt.want != "") || (got != nil && gotURL != tt.want) {
+			t.Errorf("proxy(%s) = %v, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetTopReleases returns the Top 100 releases of a genre.
+func (c *Client) GetTopReleases(genreID int) ([]Release, error) {
+	return c.GetTopReleasesCtx(context.Background(), genreID)
+}
+
+// GetTopReleasesCtx is like GetTopReleases but uses ctx for its requests.
+func (c *Client) GetTopReleasesCtx(ctx context.Context, genreID int) (_ []Release, err error) {
+	defer c.observeFetch("top_releases", time.Now(), &err)
+
+	var releases []Release
+	pageURL := fmt.Sprintf("%s/catalog/genres/%d/top/100?type=releases&per_page=%d", c.BaseURL, genreID, c.perPage(0))
+
+	ctx = c.withProgress(ctx)
+	for pageURL != "" {
+		var releaseResp ReleaseResponse
+		if err := c.getJSON(ctx, pageURL, "get top releases", &releaseResp); err != nil {
+			return nil, err
+		}
+		progressFrom(ctx).add(1)
+		if releaseResp.Count > 0 && len(releaseResp.Results) > 0 {
+			progressFrom(ctx).setTotal((releaseResp.Count + len(releaseResp.Results) - 1) / len(releaseResp.Results))
+		}
+
+		releases = append(releases, releaseResp.Results...)
+		if len(releaseResp.Results) == 0 {
+			break
+		}
+
+		pageURL, err = c.resolveURL(releaseResp.Next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return releases, nil
+}
@@ -0,0 +1,35 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTopReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/genres/5/top/100" || r.URL.Query().Get("type") != "releases" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"count": 1, "results": [{"id": 5001, "name": "Night Drive EP", "artists": [{"id": 1, "name": "Example Artist"}], "label": {"id": 301, "name": "Example Records"}, "track_count": 4, "publish_date": "2024-05-10"}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	releases, err := client.GetTopReleases(5)
+	if err != nil {
+		t.Fatalf("GetTopReleases failed: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(releases))
+	}
+	r := releases[0]
+	if r.Name != "Night Drive EP" || r.TrackCount != 4 || r.LabelName() != "Example Records" || r.ArtistNames() != "Example Artist" {
+		t.Errorf("Unexpected release: %+v", r)
+	}
+}
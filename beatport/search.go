@@ -0,0 +1,30 @@
+package beatport
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SearchTracks searches the whole catalog for tracks matching query, e.g. an
+// artist and title. It returns the first PageSize results.
+func (c *Client) SearchTracks(query string) ([]Track, error) {
+	return c.SearchTracksCtx(context.Background(), query)
+}
+
+// SearchTracksCtx is like SearchTracks but uses ctx for its requests.
+func (c *Client) SearchTracksCtx(ctx context.Context, query string) (_ []Track, err error) {
+	defer c.observeFetch("search", time.Now(), &err)
+
+	params := url.Values{
+		"q":        {query},
+		"type":     {"tracks"},
+		"per_page": {fmt.Sprint(c.perPage(0))},
+	}
+	var searchResp SearchResponse
+	if err := c.getJSON(ctx, c.BaseURL+"/catalog/search?"+params.Encode(), fmt.Sprintf("search for %q", query), &searchResp); err != nil {
+		return nil, err
+	}
+	return searchResp.Tracks, nil
+}
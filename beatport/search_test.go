@@ -0,0 +1,34 @@
+package beatport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchTracks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/search" {
+			t.Errorf("Expected path /catalog/search, got %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("q") != "night drive & co" || q.Get("type") != "tracks" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"tracks": [{"id": 1, "name": "Night Drive", "bpm": 124}]}`)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+
+	tracks, err := client.SearchTracks("night drive & co")
+	if err != nil {
+		t.Fatalf("SearchTracks failed: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].BPM != 124 {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+}
@@ -0,0 +1,54 @@
+package beatport
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var noopTracer = noop.NewTracerProvider().Tracer("")
+
+// tracer returns the configured tracer, or one that records nothing if there
+// is none.
+func (c *Client) tracer() trace.Tracer {
+	if c.Tracer == nil {
+		return noopTracer
+	}
+	return c.Tracer
+}
+
+// startAttemptSpan starts the span of one attempt at req, as a child of the
+// span in the request's context. It returns req with the span's context.
+func (c *Client) startAttemptSpan(req *http.Request, attempt int) (*http.Request, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if attempt > 0 {
+		attrs = append(attrs, attribute.Int("http.request.resend_count", attempt))
+	}
+	ctx, span := c.tracer().Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return req.WithContext(ctx), span
+}
+
+// endAttemptSpan records the outcome of an attempt and ends its span.
+func endAttemptSpan(span trace.Span, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case resp.StatusCode >= 400:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode))
+	default:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	span.End()
+}
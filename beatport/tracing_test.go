@@ -0,0 +1,100 @@
+package beatport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer records the spans it starts.
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &recordingSpan{parent: trace.SpanContextFromContext(ctx), attrs: map[attribute.Key]attribute.Value{}}
+	for _, kv := range cfg.Attributes() {
+		span.attrs[kv.Key] = kv.Value
+	}
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	noop.Span
+	parent trace.SpanContext
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	ended  bool
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) { s.status = code }
+func (s *recordingSpan) End(options ...trace.SpanEndOption)            { s.ended = true }
+
+func TestTracer(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, _ := NewClient()
+	client.BaseURL = server.URL
+	client.Token = &OAuthToken{AccessToken: "test-token"}
+	client.RateLimit = 0
+	client.RetryBaseDelay = time.Millisecond
+	client.Tracer = tracer
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	if _, err := client.GetGenresCtx(ctx); err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected a span per attempt, got %d", len(tracer.spans))
+	}
+	for i, span := range tracer.spans {
+		if !span.ended {
+			t.Errorf("Span %d was not ended", i)
+		}
+		if span.parent.TraceID() != parent.TraceID() {
+			t.Errorf("Span %d is not a child of the caller's span", i)
+		}
+		if span.attrs["http.request.method"].AsString() != "GET" {
+			t.Errorf("Span %d: unexpected attributes %v", i, span.attrs)
+		}
+	}
+
+	first, retry := tracer.spans[0], tracer.spans[1]
+	if first.attrs["http.response.status_code"].AsInt64() != http.StatusBadGateway || first.status != codes.Error {
+		t.Errorf("Expected the first attempt to be a failed 502, got %v (status %v)", first.attrs, first.status)
+	}
+	if retry.attrs["http.response.status_code"].AsInt64() != http.StatusOK || retry.attrs["http.request.resend_count"].AsInt64() != 1 {
+		t.Errorf("Expected the retry to be a 200 with resend count 1, got %v", retry.attrs)
+	}
+}
@@ -0,0 +1,34 @@
+package beatport
+
+import "runtime/debug"
+
+const (
+	// ClientName identifies this module in the ClientVersionHeader.
+	ClientName = "beatport-top100"
+
+	// ClientVersionHeader is sent with every request, with the name and
+	// version of the client, e.g. "beatport-top100/v1.0.0".
+	ClientVersionHeader = "X-Client-Version"
+)
+
+// Set at build time with -ldflags "-X beatport-top100/beatport.version=...".
+var version string
+
+// Version returns the version of this module: the one set at build time, or
+// else the one Go embedded in the binary, or "(devel)" if neither is known.
+func Version() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == ClientName && info.Main.Version != "" {
+			return info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == ClientName && dep.Version != "" {
+				return dep.Version
+			}
+		}
+	}
+	return "(devel)"
+}
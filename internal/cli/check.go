@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"beatport-top100/beatport"
+)
+
+// runCheck verifies that client can log in, with the saved token or the
+// credentials, and make an authenticated request. It writes OK or FAIL with
+// the reason to w and returns the error of the step that failed. It never
+// prompts, so a login that needs a verification code fails.
+func runCheck(w io.Writer, client *beatport.Client, username, password string) error {
+	if err := client.Authenticate(username, password); err != nil {
+		fmt.Fprintf(w, "FAIL: authentication: %v\n", err)
+		return err
+	}
+	account, err := client.GetMyAccount()
+	if err != nil {
+		fmt.Fprintf(w, "FAIL: API request: %v\n", err)
+		return err
+	}
+	fmt.Fprintf(w, "OK: logged in as %s\n", account.Username)
+	return nil
+}
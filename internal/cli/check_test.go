@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestRunCheck(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	client, _ := beatport.NewClient()
+	client.Offline = true
+	var buf bytes.Buffer
+	if err := runCheck(&buf, client, "mock", "mock"); err != nil {
+		t.Fatalf("runCheck failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "OK: ") {
+		t.Errorf("Expected OK, got %q", buf.String())
+	}
+
+	// Without a saved token or credentials there is nothing to log in with
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	client, _ = beatport.NewClient()
+	client.BaseURL, client.AuthURL = server.URL, server.URL
+	client.MaxRetries = 0
+	buf.Reset()
+	err := runCheck(&buf, client, "", "")
+	if err == nil || !strings.HasPrefix(buf.String(), "FAIL: authentication: ") {
+		t.Errorf("Expected FAIL, got %q (err %v)", buf.String(), err)
+	}
+	if code := exitCode(err); code != exitAuth {
+		t.Errorf("exitCode = %d, want %d", code, exitAuth)
+	}
+}
@@ -2,203 +2,871 @@ package cli
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
+	"text/template"
+	"time"
 
 	"beatport-top100/beatport"
 
 	"golang.org/x/term"
 )
 
-type Config struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-}
+// chartNewReleases is the chart type recorded in the output of -new.
+const chartNewReleases = "new-releases"
+
+func Run() {
+	var jsonOutput bool
+	var csvOutput bool
+	var m3uOutput bool
+	var tracklistOutput, timestamps bool
+	var genreName string
+	var showSubGenres bool
+	var hype bool
+	var newReleases bool
+	var configPath string
+	var outputPath string
+	var previewDir string
+	var artworkDir string
+	var downloadWorkers int
+	var limit int
+	var minBPM, maxBPM int
+	var keyFilter string
+	var sinceDate string
+	var compatible bool
+	var refreshGenres bool
+	var verbose bool
+	var dumpDir string
+	var mock bool
+	var artistID int
+	var labelID int
+	var fieldList string
+	var searchQuery string
+	var releases bool
+	var colorMode string
+	var diffPath string
+	var templatePath string
+	var currency string
+	var sqlitePath string
+	var xlsxPath string
+	var jsonlOutput bool
+	var sortField string
+	var sortDesc bool
+	var watchInterval time.Duration
+	var timeout time.Duration
+	var proxyURL string
+	var maxTokenAge time.Duration
+	var djChartID int
+	var showVersion bool
+	var listGenres bool
+	var authOnly bool
+	var check bool
+	var noSave bool
+	var genreIDs bool
+	flag.BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	flag.BoolVar(&jsonlOutput, "jsonl", false, "Output one JSON object per track per line (JSON Lines)")
+	flag.BoolVar(&csvOutput, "csv", false, "Output in CSV format")
+	flag.BoolVar(&m3uOutput, "m3u", false, "Output as an extended M3U8 playlist of track previews")
+	flag.BoolVar(&tracklistOutput, "tracklist", false, "Output a plain numbered tracklist (1. Artist - Title (Mix)), e.g. for a mix description")
+	flag.BoolVar(&timestamps, "timestamps", false, "Start every line of -tracklist with a [00:00] timestamp placeholder")
+	flag.StringVar(&fieldList, "fields", "", "Comma-separated track fields to output with -csv, -xlsx, -json or -jsonl, e.g. rank,artist,title,bpm")
+	flag.BoolVar(&authOnly, "auth", false, "Log in, save the token and exit")
+	flag.BoolVar(&check, "check", false, "Check that logging in and an API request work, print OK or FAIL and exit")
+	flag.BoolVar(&noSave, "no-save", false, "Never write credentials, the token, cookies or caches to disk")
+	flag.BoolVar(&listGenres, "list-genres", false, "List the available genres and exit")
+	flag.StringVar(&genreName, "genre", "", "Genre to fetch by name, slug or ID, or a comma-separated list of genres (skips the interactive prompt)")
+	flag.BoolVar(&genreIDs, "genre-ids", false, "Include the genre's name and ID in the text header and as CSV columns")
+	flag.BoolVar(&showSubGenres, "subgenres", false, "List the sub-genres of the selected genre")
+	flag.BoolVar(&releases, "releases", false, "Fetch the Top 100 releases (EPs, albums) of the genre instead of tracks")
+	flag.BoolVar(&hype, "hype", false, "Fetch the Hype Top 100 chart instead of the Top 100")
+	flag.BoolVar(&newReleases, "new", false, "Fetch the newest releases of the genre instead of the Top 100")
+	flag.BoolVar(&mock, "mock", false, "Serve built-in sample data instead of contacting Beatport (no login needed)")
+	flag.BoolVar(&verbose, "v", false, "Log every request to stderr (shorthand for -verbose)")
+	flag.BoolVar(&verbose, "verbose", false, "Log every request to stderr")
+	flag.StringVar(&dumpDir, "debug-dump", "", "Write every request and response, with credentials redacted, to a file in this directory")
+	flag.BoolVar(&refreshGenres, "refresh-genres", false, "Fetch the genre list again instead of using the cached one")
+	flag.StringVar(&searchQuery, "search", "", "Search the whole catalog for tracks matching this query instead of fetching a genre chart")
+	flag.IntVar(&djChartID, "chart", 0, "Print the tracklist of the DJ chart with this ID instead of a genre chart")
+	flag.IntVar(&artistID, "artist", 0, "Print the recent tracks of the artist with this ID instead of a genre chart")
+	flag.IntVar(&labelID, "label", 0, "Print the recent tracks of the label with this ID instead of a genre chart")
+	flag.StringVar(&diffPath, "diff", "", "Compare the chart with one saved earlier with -json and show what moved")
+	flag.DurationVar(&watchInterval, "watch", 0, "Keep running and fetch the chart again at this interval (e.g. 15m), printing only the changes")
+	flag.StringVar(&sortField, "sort", "", "Sort the tracks by rank, bpm, key (Camelot order), artist or title")
+	flag.BoolVar(&sortDesc, "desc", false, "Sort in descending order (with -sort)")
+	flag.IntVar(&limit, "limit", 0, "Only output the top N tracks (0 for all)")
+	flag.IntVar(&minBPM, "min-bpm", 0, "Only output tracks of at least this BPM")
+	flag.IntVar(&maxBPM, "max-bpm", 0, "Only output tracks of at most this BPM")
+	flag.StringVar(&keyFilter, "key", "", "Only output tracks in this key, in Camelot notation (e.g. 8A)")
+	flag.StringVar(&sinceDate, "since", "", "Only output tracks released on or after this date (YYYY-MM-DD)")
+	flag.BoolVar(&compatible, "compatible", false, "With -key, also output tracks in harmonically compatible keys")
+	flag.StringVar(&previewDir, "download-previews", "", "Download the track previews into this directory")
+	flag.StringVar(&artworkDir, "download-artwork", "", "Download the cover art of the tracks into this directory")
+	flag.IntVar(&downloadWorkers, "download-workers", defaultDownloadWorkers, "Number of previews or artwork files to download at the same time")
+	flag.StringVar(&colorMode, "color", "auto", "Color the text output: auto, always or never")
+	flag.StringVar(&templatePath, "template", "", "Format every track with this Go text/template file")
+	flag.DurationVar(&timeout, "timeout", beatport.DefaultTimeout, "Time limit for each request, e.g. 1m (0 for no limit)")
+	flag.StringVar(&proxyURL, "proxy", "", "Send requests through this HTTP or SOCKS5 proxy, e.g. socks5://localhost:1080 (default: HTTP_PROXY, HTTPS_PROXY or ALL_PROXY)")
+	flag.DurationVar(&maxTokenAge, "max-token-age", 0, "Log in again once the saved token is older than this, e.g. 168h, however often it was refreshed (0 for no limit)")
+	flag.StringVar(&currency, "currency", "", "Currency to show prices in, e.g. EUR (default: your account's currency)")
+	flag.StringVar(&sqlitePath, "sqlite", "", "Record the chart in this SQLite database instead of printing it")
+	flag.StringVar(&xlsxPath, "xlsx", "", "Write the tracks to this Excel workbook instead of printing them")
+	flag.StringVar(&outputPath, "o", "", "Write the output to this file instead of stdout")
+	flag.BoolVar(&showVersion, "version", false, "Print the version and exit")
+	flag.StringVar(&configPath, "config", "", "Path to the config file (default ./config.json if present, else $XDG_CONFIG_HOME/beatport-top100/config.json)")
+	flag.Parse()
+
+	if showVersion {
+		fmt.Println(versionString())
+		return
+	}
 
-func loadConfig() (*Config, error) {
-	file, err := os.Open("config.json")
+	fields, err := parseFields(fieldList)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // Config doesn't exist, not an error
+		log.Fatalf("Invalid -fields: %v", err)
+	}
+	var tmpl *template.Template
+	if templatePath != "" {
+		tmpl, err = parseTemplate(templatePath)
+		if err != nil {
+			log.Fatalf("Invalid -template: %v", err)
 		}
-		return nil, err
 	}
-	defer file.Close()
-
-	var config Config
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
-		return nil, err
+	if sqlitePath != "" && (artistID != 0 || labelID != 0 || djChartID != 0 || searchQuery != "" || releases) {
+		log.Fatalf("-sqlite can only be used with genre track charts")
 	}
-	return &config, nil
-}
-
-func saveConfig(username, password string) {
-	config := Config{
-		Username: username,
-		Password: password,
+	if watchInterval != 0 && (watchInterval < time.Minute || artistID != 0 || labelID != 0 || djChartID != 0 || searchQuery != "" || releases || sqlitePath != "" || xlsxPath != "") {
+		log.Fatalf("-watch needs an interval of at least 1m and can only be used with genre track charts")
 	}
-	file, err := os.Create("config.json")
-	if err != nil {
-		log.Printf("Warning: Failed to create config.json: %v", err)
-		return
+	if authOnly && noSave {
+		log.Fatalf("-auth saves the token and can't be used with -no-save")
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "    ")
-	if err := encoder.Encode(config); err != nil {
-		log.Printf("Warning: Failed to write to config.json: %v", err)
+	if newReleases && (hype || releases || watchInterval != 0 || diffPath != "" || sqlitePath != "") {
+		log.Fatalf("-new can't be used with -hype, -releases, -watch, -diff or -sqlite")
+	}
+	if downloadWorkers < 1 {
+		log.Fatalf("-download-workers must be at least 1")
+	}
+	if minBPM < 0 || maxBPM < 0 || (maxBPM > 0 && minBPM > maxBPM) {
+		log.Fatalf("-min-bpm and -max-bpm must be positive, with -min-bpm at most -max-bpm")
+	}
+	if keyFilter != "" {
+		if _, err := beatport.ParseCamelot(keyFilter); err != nil {
+			log.Fatalf("Invalid -key: %v", err)
+		}
+	} else if compatible {
+		log.Fatalf("-compatible needs -key")
+	}
+	if timestamps && !tracklistOutput {
+		log.Fatalf("-timestamps needs -tracklist")
+	}
+	var since time.Time
+	if sinceDate != "" {
+		if since, err = time.Parse(beatport.ReleaseDateLayout, sinceDate); err != nil {
+			log.Fatalf("Invalid -since, expected a date like 2024-05-01: %v", err)
+		}
+	}
+	filtering := minBPM > 0 || maxBPM > 0 || keyFilter != "" || sinceDate != ""
+	if filtering && (releases || diffPath != "" || watchInterval != 0 || sqlitePath != "") {
+		log.Fatalf("-min-bpm, -max-bpm, -key and -since can't be used with -releases, -diff, -watch or -sqlite")
+	}
+	if sortField != "" {
+		if err := validateSort(sortField); err != nil {
+			log.Fatalf("Invalid -sort: %v", err)
+		}
+	}
+	if colorMode != "auto" && colorMode != "always" && colorMode != "never" {
+		log.Fatalf("Invalid -color %q: must be auto, always or never", colorMode)
 	}
-}
 
-func Run() {
-	var jsonOutput bool
-	var csvOutput bool
-	flag.BoolVar(&jsonOutput, "json", false, "Output in JSON format")
-	flag.BoolVar(&csvOutput, "csv", false, "Output in CSV format")
-	flag.Parse()
+	// Status messages would corrupt machine-readable output
+	quiet := listGenres || jsonOutput || jsonlOutput || csvOutput || m3uOutput || tracklistOutput || templatePath != "" || sqlitePath != "" || xlsxPath != ""
+
+	if configPath == "" {
+		configPath = defaultConfigPath()
+	}
 
 	reader := bufio.NewReader(os.Stdin)
-	config, err := loadConfig()
+	config, err := loadConfig(configPath)
 	if err != nil {
 		log.Printf("Warning: Failed to load config: %v", err)
 	}
 
+	// Note: The original code prompted for genre AFTER login.
+	// Let's keep the flow.
+
+	transport, err := beatport.ProxyTransport(proxyURL)
+	if err != nil {
+		log.Fatalf("Invalid -proxy: %v", err)
+	}
+	client, err := beatport.NewClientWithHTTPClient(&http.Client{Timeout: timeout, Transport: transport})
+	if err != nil {
+		log.Fatalf("Error creating client: %v", err)
+	}
+
+	client.Currency = strings.ToUpper(currency)
+	client.DumpDir = dumpDir
+	client.Ephemeral = noSave
+	client.MaxTokenAge = maxTokenAge
+	// Show the progress of long fetches, unless the output is for a program
+	// or the request log would interleave with it
+	var bar *progressBar
+	if !quiet && !verbose && watchInterval == 0 && term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd())) {
+		bar = &progressBar{w: os.Stderr}
+		client.Progress = bar.update
+	}
+	if verbose {
+		client.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	// Keep the token and cached client ID next to the config file
+	stateDir := filepath.Dir(configPath)
+	if mock {
+		// Don't let the fake token and sample genres replace the real ones
+		client.Offline = true
+		stateDir, err = os.MkdirTemp("", "beatport-top100-mock")
+		if err != nil {
+			log.Fatalf("Error creating temporary directory: %v", err)
+		}
+		defer os.RemoveAll(stateDir)
+	}
+	if !noSave {
+		if err := os.MkdirAll(stateDir, 0o700); err != nil {
+			log.Fatalf("Error creating %s: %v", stateDir, err)
+		}
+	}
+	client.TokenPath = filepath.Join(stateDir, beatport.TokenFile)
+	client.ClientIDPath = filepath.Join(stateDir, beatport.ClientIDFile)
+	client.GenresPath = filepath.Join(stateDir, beatport.GenresFile)
+	client.CookiesPath = filepath.Join(stateDir, beatport.CookiesFile)
+	if err := client.LoadCookies(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: Failed to load cookies: %v", err)
+	}
+
 	var username, password string
+	var prompted bool
+	if mock {
+		// The offline client accepts any credentials
+		username, password = "mock", "mock"
+	} else if config != nil {
+		username, password = config.Username, config.Password
+	}
 
-	if config != nil && config.Username != "" && config.Password != "" {
-		if !jsonOutput && !csvOutput {
-			fmt.Println("Using credentials from config.json")
+	if check {
+		err := runCheck(os.Stdout, client, username, password)
+		if cerr := client.Close(); cerr != nil {
+			log.Printf("Warning: Failed to save cookies: %v", cerr)
 		}
-		username = config.Username
-		password = config.Password
-	} else {
-		fmt.Print("Enter Beatport Username: ")
-		username, _ = reader.ReadString('\n')
-		username = strings.TrimSpace(username)
+		if err != nil {
+			os.Exit(exitCode(err))
+		}
+		return
+	}
 
-		fmt.Print("Enter Beatport Password: ")
-		bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Authenticating...")
+	}
+	// A saved token makes the credentials unnecessary, so only ask for them
+	// when it can't be used
+	err = client.Authenticate(username, password)
+	if errors.Is(err, beatport.ErrNoCredentials) {
+		username, password, prompted, err = promptCredentials(config, reader, func() (string, error) {
+			return readPassword(reader)
+		})
 		if err != nil {
 			log.Fatalf("Failed to read password: %v", err)
 		}
-		password = string(bytePassword)
-		fmt.Println() // Print newline after hidden input
+		err = client.Authenticate(username, password)
+	}
+	if errors.Is(err, beatport.ErrVerificationRequired) {
+		err = verifyLogin(client, reader)
+	}
+	if err != nil {
+		fatalf(err, "Authentication failed: %v", err)
 	}
 
-	// Ask for Genre
-	// If we are in non-interactive mode (e.g. piped input or just flags), we might want to support a flag for genre too.
-	// But for now, let's stick to the existing behavior but make it testable if we wanted to inject stdin/stdout.
+	if err := client.SaveCookies(); err != nil {
+		log.Printf("Warning: Failed to save cookies: %v", err)
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Printf("Warning: Failed to save cookies: %v", err)
+		}
+	}()
 
-	// Note: The original code prompted for genre AFTER login.
-	// Let's keep the flow.
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Successfully authenticated!")
+	}
 
-	client, err := beatport.NewClient()
-	if err != nil {
-		log.Fatalf("Error creating client: %v", err)
+	// Save config if any of the credentials were entered manually
+	if !mock && !noSave && prompted {
+		fmt.Fprintf(os.Stderr, "Do you want to save credentials to %s? (y/n): ", configPath)
+		save, _ := reader.ReadString('\n')
+		save = strings.TrimSpace(save)
+		if strings.ToLower(save) == "y" {
+			saveConfig(configPath, username, password)
+			fmt.Fprintln(os.Stderr, "Credentials saved.")
+		}
 	}
 
-	if !jsonOutput && !csvOutput {
-		fmt.Println("Authenticating...")
+	if authOnly {
+		if account, err := client.GetMyAccount(); err != nil {
+			log.Printf("Warning: Failed to fetch the account: %v", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Logged in as %s\n", account.Username)
+		}
+		fmt.Fprintf(os.Stderr, "Token saved to %s\n", client.TokenPath)
+		return
 	}
-	if err := client.Login(username, password); err != nil {
-		log.Fatalf("Login failed: %v", err)
+
+	// filterTracks drops the tracks that don't match the filters, before
+	// the result holding them is built.
+	filterTracks := func(tracks []beatport.Track) []beatport.Track {
+		tracks = beatport.FilterByBPM(tracks, minBPM, maxBPM)
+		if keyFilter != "" {
+			tracks = beatport.FilterByKey(tracks, keyFilter, compatible)
+		}
+		if !since.IsZero() {
+			if verbose {
+				undated := 0
+				for _, track := range tracks {
+					if _, err := track.ReleaseTime(); err != nil {
+						undated++
+					}
+				}
+				if undated > 0 {
+					log.Printf("Leaving out %d tracks without a release date", undated)
+				}
+			}
+			tracks = beatport.FilterSince(tracks, since)
+		}
+		return tracks
 	}
 
-	// Authorize and get token
-	code, err := client.Authorize()
-	if err != nil {
-		log.Fatalf("Authorization failed: %v", err)
+	// prepareTracks sorts the tracks in place and downloads their files, as
+	// requested by the flags.
+	prepareTracks := func(tracks []beatport.Track) {
+		if sortField != "" {
+			sortTracks(tracks, sortField, sortDesc)
+		}
+		if previewDir != "" {
+			downloadFiles(tracks, previewDir, "preview", quiet, downloadWorkers, client.DownloadPreview, beatport.ErrNoPreview)
+		}
+		if artworkDir != "" {
+			downloadFiles(tracks, artworkDir, "artwork", quiet, downloadWorkers, client.DownloadArtwork, beatport.ErrNoArtwork)
+		}
 	}
 
-	if err := client.GetToken(code); err != nil {
-		log.Fatalf("Token exchange failed: %v", err)
+	// writeOutput writes tracks in the selected format; result is what -json
+	// encodes.
+	writeOutput := func(title string, tracks []beatport.Track, result any) {
+		bar.clear()
+		// result holds the same tracks, so it is sorted along with them
+		prepareTracks(tracks)
+
+		if xlsxPath != "" {
+			xlsxFields := fields
+			if xlsxFields == nil {
+				xlsxFields, _ = parseFields(defaultXLSXFields)
+			}
+			if err := writeXLSX(xlsxPath, tracks, xlsxFields); err != nil {
+				log.Fatalf("Error writing %s: %v", xlsxPath, err)
+			}
+			return
+		}
+
+		out, closeOut := openOutput(outputPath)
+		defer closeOut()
+
+		switch {
+		case tmpl != nil:
+			if err := writeTemplate(out, tmpl, tracks); err != nil {
+				log.Fatalf("Error executing template: %v", err)
+			}
+		case jsonOutput:
+			if fields != nil {
+				projected, err := projectResult(result, tracks, fields)
+				if err != nil {
+					log.Fatalf("Error encoding JSON: %v", err)
+				}
+				result = projected
+			}
+			if err := writeJSON(out, result); err != nil {
+				log.Fatalf("Error encoding JSON: %v", err)
+			}
+		case jsonlOutput:
+			if err := writeJSONLines(out, tracks, fields); err != nil {
+				log.Fatalf("Error encoding JSON: %v", err)
+			}
+		case csvOutput:
+			csvFields := fields
+			if csvFields == nil {
+				csvFields, _ = parseFields(defaultCSVFields)
+			}
+			if r, ok := result.(beatport.ChartResult); ok && genreIDs {
+				csvFields = append(genreFields(r.Genre), csvFields...)
+			}
+			if err := writeCSV(out, tracks, csvFields); err != nil {
+				log.Fatalf("Error writing CSV: %v", err)
+			}
+		case tracklistOutput:
+			writeTracklist(out, tracks, timestamps)
+		case m3uOutput:
+			writeM3U(out, tracks)
+		default:
+			writeText(out, title, tracks, useColor(colorMode, out))
+		}
 	}
 
-	if !jsonOutput && !csvOutput {
-		fmt.Println("Successfully authenticated!")
+	if artistID != 0 {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Fetching tracks of artist %d...\n", artistID)
+		}
+		fetchedAt := time.Now().UTC()
+		artist, err := client.GetArtist(artistID)
+		if err != nil {
+			fatalf(err, "Error fetching artist: %v", err)
+		}
+		tracks, err := client.GetArtistTracks(artistID)
+		if err != nil && len(tracks) == 0 {
+			fatalf(err, "Error fetching tracks of %s: %v", artist.Name, err)
+		} else if err != nil {
+			log.Printf("Warning: Only some tracks of %s could be fetched: %v", artist.Name, err)
+		}
+		if limit > 0 && len(tracks) > limit {
+			tracks = tracks[:limit]
+		}
+		tracks = filterTracks(tracks)
+		writeOutput(artist.Name, tracks, artistResult{
+			Artist:    *artist,
+			FetchedAt: fetchedAt,
+			Tracks:    orEmpty(tracks),
+		})
+		return
 	}
 
-	// Save config if it was manual entry
-	if config == nil || config.Username == "" {
-		fmt.Print("Do you want to save credentials to config.json? (y/n): ")
-		save, _ := reader.ReadString('\n')
-		save = strings.TrimSpace(save)
-		if strings.ToLower(save) == "y" {
-			saveConfig(username, password)
-			fmt.Println("Credentials saved.")
+	if labelID != 0 {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Fetching tracks of label %d...\n", labelID)
+		}
+		fetchedAt := time.Now().UTC()
+		label, err := client.GetLabel(labelID)
+		if err != nil {
+			fatalf(err, "Error fetching label: %v", err)
 		}
+		tracks, err := client.GetLabelTracks(labelID)
+		if err != nil && len(tracks) == 0 {
+			fatalf(err, "Error fetching tracks of %s: %v", label.Name, err)
+		} else if err != nil {
+			log.Printf("Warning: Only some tracks of %s could be fetched: %v", label.Name, err)
+		}
+		if limit > 0 && len(tracks) > limit {
+			tracks = tracks[:limit]
+		}
+		tracks = filterTracks(tracks)
+		writeOutput(label.Name, tracks, labelResult{
+			Label:     *label,
+			FetchedAt: fetchedAt,
+			Tracks:    orEmpty(tracks),
+		})
+		return
 	}
 
-	fmt.Print("Enter Genre (e.g. Techno): ")
-	genreName, _ := reader.ReadString('\n')
-	genreName = strings.TrimSpace(genreName)
+	if refreshGenres {
+		if err := client.InvalidateGenreCache(); err != nil {
+			log.Printf("Warning: Failed to clear the genre cache: %v", err)
+		}
+	}
 
-	if !jsonOutput && !csvOutput {
-		fmt.Println("Fetching genres...")
+	if listGenres {
+		genres, err := client.GetGenresCached()
+		if err != nil {
+			fatalf(err, "Error fetching genres: %v", err)
+		}
+		out, closeOut := openOutput(outputPath)
+		defer closeOut()
+		if jsonOutput {
+			if err := writeJSON(out, genres); err != nil {
+				log.Fatalf("Error encoding JSON: %v", err)
+			}
+			return
+		}
+		writeGenres(out, genres)
+		return
 	}
-	genres, err := client.GetGenres()
-	if err != nil {
-		log.Fatalf("Error fetching genres: %v", err)
+
+	if djChartID != 0 {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Fetching DJ chart %d...\n", djChartID)
+		}
+		fetchedAt := time.Now().UTC()
+		tracks, err := client.GetChartTracks(djChartID)
+		if err != nil && len(tracks) == 0 {
+			fatalf(err, "Error fetching DJ chart: %v", err)
+		} else if err != nil {
+			log.Printf("Warning: Only part of the DJ chart could be fetched: %v", err)
+		}
+		if limit > 0 && len(tracks) > limit {
+			tracks = tracks[:limit]
+		}
+		tracks = filterTracks(tracks)
+		writeOutput(fmt.Sprintf("DJ Chart %d", djChartID), tracks, djChartResult{
+			ChartID:   djChartID,
+			FetchedAt: fetchedAt,
+			Tracks:    orEmpty(tracks),
+		})
+		return
 	}
 
-	var selectedGenre *beatport.Genre
-	for _, g := range genres {
-		if strings.EqualFold(g.Name, genreName) {
-			selectedGenre = &g
-			break
+	if searchQuery != "" {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Searching for %q...\n", searchQuery)
 		}
+		fetchedAt := time.Now().UTC()
+		tracks, err := client.SearchTracks(searchQuery)
+		if err != nil {
+			fatalf(err, "Error searching tracks: %v", err)
+		}
+		if limit > 0 && len(tracks) > limit {
+			tracks = tracks[:limit]
+		}
+		tracks = filterTracks(tracks)
+		writeOutput(fmt.Sprintf("Search %q", searchQuery), tracks, searchResult{
+			Query:     searchQuery,
+			FetchedAt: fetchedAt,
+			Tracks:    orEmpty(tracks),
+		})
+		return
+	}
+
+	if genreName == "" {
+		fmt.Fprint(os.Stderr, "Enter Genre (e.g. Techno): ")
+		genreName, _ = reader.ReadString('\n')
+	}
+	genreNames := splitGenres(genreName)
+	if len(genreNames) == 0 {
+		genreNames = []string{""}
 	}
 
-	if selectedGenre == nil {
-		fmt.Printf("Genre '%s' not found. Available genres:\n", genreName)
-		for _, g := range genres {
-			fmt.Printf("- %s (ID: %d)\n", g.Name, g.ID)
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Resolving genre...")
+	}
+	var selectedGenres []beatport.Genre
+	for _, name := range genreNames {
+		genre, err := client.ResolveGenre(name)
+		if errors.Is(err, beatport.ErrGenreNotFound) {
+			genres, _ := client.GetGenresCached()
+			fmt.Fprintf(os.Stderr, "Genre '%s' not found. Available genres:\n", name)
+			writeGenres(os.Stderr, genres)
+			fatalf(err, "Please choose one of the available genres.")
+		}
+		if err != nil {
+			fatalf(err, "Error fetching genres: %v", err)
+		}
+		if !slices.ContainsFunc(selectedGenres, func(g beatport.Genre) bool { return g.ID == genre.ID }) {
+			selectedGenres = append(selectedGenres, *genre)
 		}
-		log.Fatalf("Please choose one of the available genres.")
 	}
 
-	if !jsonOutput && !csvOutput {
-		fmt.Printf("Fetching Top 100 for %s (ID: %d)...\n", selectedGenre.Name, selectedGenre.ID)
+	chartName, chartType := "Top 100", beatport.ChartTop100
+	if hype {
+		chartName, chartType = "Hype Top 100", beatport.ChartHype100
 	}
-	tracks, err := client.GetTop100(selectedGenre.ID)
-	if err != nil {
-		log.Fatalf("Error fetching Top 100: %v", err)
+	if newReleases {
+		chartName, chartType = "Newest", chartNewReleases
 	}
 
-	if jsonOutput {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(tracks); err != nil {
-			log.Fatalf("Error encoding JSON: %v", err)
+	if len(selectedGenres) > 1 {
+		if releases || newReleases || showSubGenres || diffPath != "" || watchInterval > 0 || jsonlOutput || m3uOutput || tracklistOutput || tmpl != nil || xlsxPath != "" {
+			log.Fatalf("-releases, -new, -subgenres, -diff, -watch, -jsonl, -m3u, -tracklist, -template and -xlsx can only be used with a single genre")
+		}
+		ids := make([]int, len(selectedGenres))
+		for i, g := range selectedGenres {
+			ids[i] = g.ID
+		}
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Fetching %s for %d genres...\n", chartName, len(ids))
+		}
+		fetchedAt := time.Now().UTC()
+		charts, err := client.GetGenreChartMulti(ids, chartType, limit)
+		if err != nil {
+			if len(charts) == 0 {
+				fatalf(err, "Error fetching %s: %v", chartName, err)
+			}
+			log.Printf("Warning: %v", err)
+		}
+		bar.clear()
+
+		var results []beatport.ChartResult
+		for _, genre := range selectedGenres {
+			tracks, ok := charts[genre.ID]
+			if !ok {
+				continue
+			}
+			tracks = filterTracks(tracks)
+			prepareTracks(tracks)
+			results = append(results, beatport.ChartResult{
+				Genre:     genre,
+				ChartType: chartType,
+				FetchedAt: fetchedAt,
+				Tracks:    orEmpty(tracks),
+			})
+		}
+		if sqlitePath != "" {
+			for _, result := range results {
+				if err := writeSQLite(sqlitePath, result); err != nil {
+					log.Fatalf("Error writing to %s: %v", sqlitePath, err)
+				}
+			}
+			return
+		}
+
+		out, closeOut := openOutput(outputPath)
+		defer closeOut()
+		switch {
+		case jsonOutput:
+			if err := writeMultiJSON(out, results, fields); err != nil {
+				log.Fatalf("Error encoding JSON: %v", err)
+			}
+		case csvOutput:
+			csvFields := fields
+			if csvFields == nil {
+				csvFields, _ = parseFields(defaultCSVFields)
+			}
+			if err := writeMultiCSV(out, results, csvFields); err != nil {
+				log.Fatalf("Error writing CSV: %v", err)
+			}
+		default:
+			writeMultiText(out, chartName, results, genreIDs, useColor(colorMode, out))
+		}
+		return
+	}
+	selectedGenre := &selectedGenres[0]
+
+	if showSubGenres {
+		subGenres, err := client.GetSubGenres(selectedGenre.ID)
+		if err != nil {
+			fatalf(err, "Error fetching sub-genres: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Sub-genres of %s:\n", selectedGenre.Name)
+		for _, g := range subGenres {
+			fmt.Fprintf(os.Stderr, "- %s (ID: %d)\n", g.Name, g.ID)
 		}
+	}
+
+	if releases {
+		if m3uOutput || jsonlOutput || tracklistOutput || fields != nil || tmpl != nil || previewDir != "" || artworkDir != "" {
+			log.Fatalf("-m3u, -jsonl, -tracklist, -fields, -template, -download-previews and -download-artwork can't be used with -releases")
+		}
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Fetching Top 100 releases for %s (ID: %d)...\n", selectedGenre.Name, selectedGenre.ID)
+		}
+		fetchedAt := time.Now().UTC()
+		chart, err := client.GetTopReleases(selectedGenre.ID)
+		if err != nil {
+			fatalf(err, "Error fetching Top 100 releases: %v", err)
+		}
+		if limit > 0 && len(chart) > limit {
+			chart = chart[:limit]
+		}
+		bar.clear()
+		writeReleases(outputPath, jsonOutput, csvOutput, releaseChartResult{
+			Genre:     *selectedGenre,
+			FetchedAt: fetchedAt,
+			Releases:  orEmpty(chart),
+		})
 		return
 	}
 
-	if csvOutput {
-		// Simple CSV output
-		fmt.Println("Artist,Title,Mix Name")
-		for _, track := range tracks {
-			artistName := ""
-			if len(track.Artists) > 0 {
-				artistName = track.Artists[0].Name
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Fetching %s for %s (ID: %d)...\n", chartName, selectedGenre.Name, selectedGenre.ID)
+	}
+
+	if watchInterval > 0 {
+		var prev *beatport.ChartResult
+		if diffPath != "" {
+			if prev, err = readChart(diffPath); err != nil {
+				log.Fatalf("Error loading %s: %v", diffPath, err)
 			}
-			fmt.Printf("%s,%s,%s\n", artistName, track.Name, track.MixName)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		watch := chartWatch{
+			client:    client,
+			genre:     *selectedGenre,
+			chartType: chartType,
+			chartName: chartName,
+			limit:     limit,
+			genreID:   genreIDs,
+			login: func(ctx context.Context) error {
+				return client.AuthenticateCtx(ctx, username, password)
+			},
+		}
+		out, closeOut := openOutput(outputPath)
+		defer closeOut()
+		if err := watch.run(ctx, out, prev, watchInterval, jsonOutput, useColor(colorMode, out)); err != nil {
+			log.Fatalf("Error writing output: %v", err)
+		}
+		return
+	}
+	fetchedAt := time.Now().UTC()
+	if newReleases {
+		tracks, err := client.GetNewReleases(selectedGenre.ID, limit)
+		if err != nil && len(tracks) == 0 {
+			fatalf(err, "Error fetching new releases: %v", err)
+		} else if err != nil {
+			log.Printf("Warning: Only some new releases could be fetched: %v", err)
+		}
+		tracks = filterTracks(tracks)
+		writeOutput(genreTitle(*selectedGenre, chartName, genreIDs), tracks, beatport.ChartResult{
+			Genre:     *selectedGenre,
+			ChartType: chartType,
+			FetchedAt: fetchedAt,
+			Tracks:    orEmpty(tracks),
+		})
+		return
+	}
+	tracks, err := client.GetGenreChart(selectedGenre.ID, chartType, limit)
+	if err != nil {
+		fatalf(err, "Error fetching %s: %v", chartName, err)
+	}
+
+	if diffPath != "" {
+		old, err := readChart(diffPath)
+		if err != nil {
+			log.Fatalf("Error loading %s: %v", diffPath, err)
+		}
+		// Compare like with like when only the top of the chart was fetched
+		if limit > 0 && len(old.Tracks) > limit {
+			old.Tracks = old.Tracks[:limit]
+		}
+		diff := beatport.DiffCharts(old.Tracks, tracks)
+		out, closeOut := openOutput(outputPath)
+		defer closeOut()
+		if jsonOutput {
+			if err := writeJSON(out, diff); err != nil {
+				log.Fatalf("Error encoding JSON: %v", err)
+			}
+			return
+		}
+		writeDiff(out, chartName, old, diff, tracks)
+		return
+	}
+
+	tracks = filterTracks(tracks)
+	result := beatport.ChartResult{
+		Genre:     *selectedGenre,
+		ChartType: chartType,
+		FetchedAt: fetchedAt,
+		Tracks:    orEmpty(tracks),
+	}
+	if sqlitePath != "" {
+		if err := writeSQLite(sqlitePath, result); err != nil {
+			log.Fatalf("Error writing to %s: %v", sqlitePath, err)
 		}
 		return
 	}
+	writeOutput(genreTitle(*selectedGenre, chartName, genreIDs), tracks, result)
+}
+
+// readPassword reads a password from the terminal without echoing it. If
+// stdin is not a terminal, e.g. when the password is piped in, it reads the
+// next line of reader instead.
+func readPassword(reader *bufio.Reader) (string, error) {
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		password, err := readPasswordLine(reader)
+		fmt.Fprintln(os.Stderr)
+		return password, err
+	}
+	password, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr) // Print newline after hidden input
+	return string(password), err
+}
+
+// readPasswordLine reads a password from the next line of reader. Only the
+// line ending is stripped, as spaces may be part of the password.
+func readPasswordLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil // The last line doesn't need a newline
+	}
+	if err == io.EOF {
+		return "", errors.New("no password on stdin")
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
 
-	fmt.Println("\nTop 100 Tracks:")
-	for i, track := range tracks {
-		artistName := ""
-		if len(track.Artists) > 0 {
-			artistName = track.Artists[0].Name
+// verifyLogin asks for the verification code Beatport emailed and finishes
+// the authentication with it.
+func verifyLogin(client *beatport.Client, reader *bufio.Reader) error {
+	fmt.Fprint(os.Stderr, "Beatport sent a verification code to your email. Enter the code: ")
+	code, _ := reader.ReadString('\n')
+	if err := client.SubmitVerificationCode(strings.TrimSpace(code)); err != nil {
+		return err
+	}
+	authCode, err := client.Authorize()
+	if err != nil {
+		return err
+	}
+	return client.GetToken(authCode)
+}
+
+// useColor reports whether the text output to out should be colored. In auto
+// mode it is when out is a terminal and NO_COLOR is not set.
+func useColor(mode string, out io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// openOutput returns the file at path to write the output to, or stdout if
+// path is empty, along with a function that closes it.
+func openOutput(path string) (io.Writer, func()) {
+	if path == "" {
+		return os.Stdout, func() {}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Error creating output file: %v", err)
+	}
+	return file, func() { file.Close() }
+}
+
+// writeReleases writes a release chart as JSON, CSV or text to outputPath, or
+// to stdout if it is empty.
+func writeReleases(outputPath string, jsonOutput, csvOutput bool, result releaseChartResult) {
+	out, closeOut := openOutput(outputPath)
+	defer closeOut()
+
+	switch {
+	case jsonOutput:
+		if err := writeJSON(out, result); err != nil {
+			log.Fatalf("Error encoding JSON: %v", err)
+		}
+	case csvOutput:
+		if err := writeReleasesCSV(out, result.Releases); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
 		}
-		fmt.Printf("%d. %s - %s (%s)\n", i+1, artistName, track.Name, track.MixName)
+	default:
+		writeReleasesText(out, "Top 100", result.Releases)
 	}
 }
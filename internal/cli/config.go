@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"beatport-top100/beatport"
+
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = "config.json"
+
+// configFileNames are the config files looked for, in order of preference.
+var configFileNames = []string{"config.yaml", "config.yml", configFileName}
+
+// keyringService is the service name the password is stored under in the OS
+// keyring, keyed by username.
+const keyringService = "beatport-top100"
+
+// Config holds the saved credentials. Password is only written to the file
+// when no OS keyring is available.
+type Config struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// defaultConfigPath returns the first of config.yaml, config.yml and
+// config.json that exists in the working directory, then in the user's config
+// directory ($XDG_CONFIG_HOME/beatport-top100 on Linux). If there is none, it
+// returns config.json in the user's config directory.
+func defaultConfigPath() string {
+	dirs := []string{"."}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "beatport-top100"))
+	}
+	for _, dir := range dirs {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return filepath.Join(dirs[len(dirs)-1], configFileName)
+}
+
+// isYAML reports whether the config at path is YAML rather than JSON, going by
+// its extension.
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func loadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Config doesn't exist, not an error
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var config Config
+	if isYAML(path) {
+		err = yaml.NewDecoder(file).Decode(&config)
+	} else {
+		err = json.NewDecoder(file).Decode(&config)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if config.Username != "" && config.Password == "" {
+		password, err := keyring.Get(keyringService, config.Username)
+		if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			log.Printf("Warning: Failed to read password from keyring: %v", err)
+		}
+		config.Password = password
+	}
+	return &config, nil
+}
+
+// promptCredentials returns the credentials from config, asking for the ones
+// it lacks, e.g. the password of a hand-edited config with only a username.
+// It reports whether anything had to be asked.
+func promptCredentials(config *Config, reader *bufio.Reader, readPassword func() (string, error)) (username, password string, prompted bool, err error) {
+	if config != nil {
+		username, password = config.Username, config.Password
+	}
+	if username == "" {
+		fmt.Fprint(os.Stderr, "Enter Beatport Username: ")
+		username, _ = reader.ReadString('\n')
+		username = strings.TrimSpace(username)
+		prompted = true
+	}
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Enter Beatport Password: ")
+		password, err = readPassword()
+		if err != nil {
+			return "", "", false, err
+		}
+		prompted = true
+	}
+	return username, password, prompted, nil
+}
+
+// saveConfig writes the credentials to path, keeping the password in the OS
+// keyring if there is one and falling back to the file otherwise.
+func saveConfig(path, username, password string) {
+	config := Config{
+		Username: username,
+		Password: password,
+	}
+	if err := keyring.Set(keyringService, username, password); err != nil {
+		log.Printf("Warning: No keyring available, storing the password in %s: %v", path, err)
+	} else {
+		config.Password = ""
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		log.Printf("Warning: Failed to create config directory: %v", err)
+		return
+	}
+	err := beatport.WriteFileAtomic(path, 0o600, func(w io.Writer) error {
+		if isYAML(path) {
+			return yaml.NewEncoder(w).Encode(config)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(config)
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to write to %s: %v", path, err)
+	}
+}
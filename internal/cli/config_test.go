@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestSaveConfigUsesKeyring(t *testing.T) {
+	keyring.MockInit()
+	path := filepath.Join(t.TempDir(), configFileName)
+
+	saveConfig(path, "dj", "s3cret")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("Password was written to the config file: %s", data)
+	}
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.Username != "dj" || config.Password != "s3cret" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	keyring.MockInit()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("username: dj\npassword: s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.Username != "dj" || config.Password != "s3cret" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
+
+func TestPromptCredentialsPartialConfig(t *testing.T) {
+	asked := false
+	readPassword := func() (string, error) {
+		asked = true
+		return "typed", nil
+	}
+
+	username, password, prompted, err := promptCredentials(&Config{Username: "dj"}, bufio.NewReader(strings.NewReader("")), readPassword)
+	if err != nil {
+		t.Fatalf("promptCredentials failed: %v", err)
+	}
+	if username != "dj" || password != "typed" || !prompted || !asked {
+		t.Errorf("Expected only the password to be asked, got %q/%q prompted=%v", username, password, prompted)
+	}
+
+	asked = false
+	_, _, prompted, _ = promptCredentials(&Config{Username: "dj", Password: "saved"}, bufio.NewReader(strings.NewReader("")), readPassword)
+	if prompted || asked {
+		t.Errorf("Expected a complete config not to prompt")
+	}
+
+	username, password, _, _ = promptCredentials(nil, bufio.NewReader(strings.NewReader("newuser\n")), readPassword)
+	if username != "newuser" || password != "typed" {
+		t.Errorf("Expected both credentials to be asked, got %q/%q", username, password)
+	}
+}
+
+func TestReadPasswordLine(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("dj\n pass word \r\nlast"))
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{" pass word ", "last"} {
+		password, err := readPasswordLine(reader)
+		if err != nil || password != want {
+			t.Errorf("readPasswordLine = %q, %v, want %q", password, err, want)
+		}
+	}
+	if _, err := readPasswordLine(reader); err == nil {
+		t.Error("Expected an error at the end of stdin")
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"beatport-top100/beatport"
+)
+
+// readChart loads a chart saved with -json.
+func readChart(path string) (*beatport.ChartResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var chart beatport.ChartResult
+	if err := json.Unmarshal(data, &chart); err != nil {
+		return nil, fmt.Errorf("%s is not a chart saved with -json: %w", path, err)
+	}
+	return &chart, nil
+}
+
+// writeDiff writes the current chart with a marker showing how each track
+// moved since the old chart, followed by the tracks that dropped out.
+func writeDiff(w io.Writer, chartName string, old *beatport.ChartResult, diff beatport.ChartDiff, tracks []beatport.Track) {
+	moves := make(map[int]beatport.ChartMove, len(diff.Moves))
+	for _, m := range diff.Moves {
+		moves[m.Track.ID] = m
+	}
+
+	fmt.Fprintf(w, "\n%s changes since %s:\n", chartName, old.FetchedAt.Local().Format("2006-01-02 15:04"))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, track := range tracks {
+		marker := "NEW"
+		if m, ok := moves[track.ID]; ok {
+			switch change := m.Change(); {
+			case change > 0:
+				marker = fmt.Sprintf("▲%d", change)
+			case change < 0:
+				marker = fmt.Sprintf("▼%d", -change)
+			default:
+				marker = "="
+			}
+		}
+		fmt.Fprintf(tw, "%d.\t%s\t%s - %s (%s)\n", i+1, marker, track.ArtistNames(), track.Name, track.MixTitle())
+	}
+	tw.Flush()
+
+	if len(diff.Dropped) > 0 {
+		fmt.Fprintln(w)
+		for _, track := range diff.Dropped {
+			fmt.Fprintf(w, "OUT  %s - %s (%s)\n", track.ArtistNames(), track.Name, track.MixTitle())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"beatport-top100/beatport"
+)
+
+// defaultDownloadWorkers is how many files -download-previews and
+// -download-artwork fetch at the same time. The client's rate limiter is
+// shared by all of them, so more workers only help while the limit allows.
+const defaultDownloadWorkers = 4
+
+// downloadFiles saves a file per track into dir with download, e.g. the
+// track's preview clip, skipping the tracks download returns errMissing for.
+// Up to workers files are downloaded at the same time. A failed download
+// doesn't stop the others; the failures are logged together at the end.
+// kind names the file in messages.
+func downloadFiles(tracks []beatport.Track, dir, kind string, quiet bool, workers int, download func(beatport.Track, string) (string, error), errMissing error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatalf("Error creating %s: %v", dir, err)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make([]error, len(tracks))
+		jobs = make(chan int)
+	)
+	for i := 0; i < min(workers, len(tracks)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				path, err := download(tracks[i], dir)
+				if errors.Is(err, errMissing) {
+					continue
+				}
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				if !quiet {
+					mu.Lock()
+					fmt.Fprintf(os.Stderr, "Downloaded %s\n", path)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range tracks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed int
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return
+	}
+	log.Printf("Warning: Failed to download the %s of %d track(s):", kind, failed)
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("  %q: %v", tracks[i].Name, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"beatport-top100/beatport"
+)
+
+func TestDownloadFiles(t *testing.T) {
+	var logBuf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logBuf)
+
+	errMissing := errors.New("missing")
+	tracks := make([]beatport.Track, 10)
+	for i := range tracks {
+		tracks[i] = beatport.Track{ID: i, Name: "Track " + string(rune('A'+i))}
+	}
+
+	var running, maxRunning, calls atomic.Int32
+	download := func(track beatport.Track, dir string) (string, error) {
+		calls.Add(1)
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		switch track.ID {
+		case 2, 7:
+			return "", errors.New("connection reset")
+		case 5:
+			return "", errMissing
+		}
+		return dir + "/file", nil
+	}
+
+	downloadFiles(tracks, t.TempDir(), "preview", true, 3, download, errMissing)
+
+	if got := calls.Load(); got != int32(len(tracks)) {
+		t.Errorf("download called %d times, want %d", got, len(tracks))
+	}
+	if got := maxRunning.Load(); got > 3 {
+		t.Errorf("%d downloads ran at the same time, want at most 3", got)
+	}
+	logged := logBuf.String()
+	if !strings.Contains(logged, "Failed to download the preview of 2 track(s)") {
+		t.Errorf("Expected a summary of the failures, got:\n%s", logged)
+	}
+	for _, name := range []string{"Track C", "Track H"} {
+		if !strings.Contains(logged, name) {
+			t.Errorf("Expected %s among the failures, got:\n%s", name, logged)
+		}
+	}
+	if strings.Contains(logged, "Track F") {
+		t.Errorf("Missing files shouldn't be reported as failures, got:\n%s", logged)
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"log"
+	"net"
+	"os"
+
+	"beatport-top100/beatport"
+)
+
+// Exit codes, so scripts can tell why a run failed. Anything not covered
+// below exits with exitFailure.
+const (
+	exitFailure       = 1
+	exitAuth          = 2
+	exitGenreNotFound = 3
+	exitNetwork       = 4
+	exitRateLimited   = 5
+)
+
+// exitCode returns the exit code for a run that failed with err.
+func exitCode(err error) int {
+	var authErr *beatport.AuthError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, beatport.ErrRateLimited):
+		return exitRateLimited
+	case errors.As(err, &authErr), errors.Is(err, beatport.ErrNoToken):
+		return exitAuth
+	case errors.Is(err, beatport.ErrGenreNotFound), errors.Is(err, beatport.ErrGenreAmbiguous):
+		return exitGenreNotFound
+	case errors.As(err, &netErr):
+		return exitNetwork
+	}
+	return exitFailure
+}
+
+// fatalf is like log.Fatalf, but exits with the exit code for err.
+func fatalf(err error, format string, v ...any) {
+	log.Printf(format, v...)
+	os.Exit(exitCode(err))
+}
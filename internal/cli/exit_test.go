@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"auth", &beatport.AuthError{Op: "login", Err: beatport.ErrInvalidCredentials}, exitAuth},
+		{"no token", fmt.Errorf("fetch: %w", beatport.ErrNoToken), exitAuth},
+		{"genre not found", fmt.Errorf("%w: %q", beatport.ErrGenreNotFound, "Trance"), exitGenreNotFound},
+		{"network", &url.Error{Op: "Get", URL: "https://api.beatport.com", Err: errors.New("connection refused")}, exitNetwork},
+		{"rate limited", fmt.Errorf("fetched 10 of ~100 tracks: %w", beatport.ErrRateLimited), exitRateLimited},
+		{"other", errors.New("unexpected status 500"), exitFailure},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
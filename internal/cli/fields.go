@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"beatport-top100/beatport"
+)
+
+// trackField is a track attribute that can be selected with -fields. rank is
+// the track's chart position, see trackRank.
+type trackField struct {
+	name   string
+	header string
+	value  func(rank int, t beatport.Track) any
+}
+
+var trackFields = []trackField{
+	{"rank", "Rank", func(rank int, t beatport.Track) any { return rank }},
+	{"id", "ID", func(rank int, t beatport.Track) any { return t.ID }},
+	{"artist", "Artist", func(rank int, t beatport.Track) any { return t.ArtistNames() }},
+	{"title", "Title", func(rank int, t beatport.Track) any { return t.Name }},
+	{"mix", "Mix Name", func(rank int, t beatport.Track) any { return t.MixName }},
+	{"remixers", "Remixers", func(rank int, t beatport.Track) any { return t.RemixerNames() }},
+	{"length", "Length", func(rank int, t beatport.Track) any { return t.Length }},
+	{"bpm", "BPM", func(rank int, t beatport.Track) any { return t.BPM }},
+	{"key", "Key", func(rank int, t beatport.Track) any { return t.KeyName() }},
+	{"camelot", "Camelot", func(rank int, t beatport.Track) any { return t.Key.Camelot() }},
+	{"release_date", "Release Date", func(rank int, t beatport.Track) any { return t.ReleaseDate }},
+	{"label", "Label", func(rank int, t beatport.Track) any { return t.LabelName() }},
+	{"catalog", "Catalog Number", func(rank int, t beatport.Track) any { return t.CatalogNumber }},
+	{"isrc", "ISRC", func(rank int, t beatport.Track) any { return t.ISRC }},
+	{"price", "Price", func(rank int, t beatport.Track) any { return formatPrice(t.Price) }},
+	{"preview", "Preview URL", func(rank int, t beatport.Track) any { return t.PreviewURL }},
+	{"artwork", "Artwork URL", func(rank int, t beatport.Track) any { return t.ArtworkURL }},
+	{"url", "URL", func(rank int, t beatport.Track) any { return t.URL() }},
+}
+
+// genreFields are the columns that name the genre a chart was fetched for,
+// for telling the genres of combined output apart.
+func genreFields(genre beatport.Genre) []trackField {
+	return []trackField{
+		{"genre", "Genre", func(rank int, t beatport.Track) any { return genre.Name }},
+		{"genre_id", "Genre ID", func(rank int, t beatport.Track) any { return genre.ID }},
+	}
+}
+
+// defaultCSVFields are the CSV columns written when -fields is not given.
+const defaultCSVFields = "rank,artist,title,mix,length,bpm,key,camelot,release_date,label,catalog,isrc"
+
+// parseFields parses a comma-separated list of field names. An empty list
+// returns nil.
+func parseFields(list string) ([]trackField, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	var fields []trackField
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		field, ok := lookupField(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q, valid fields are: %s", name, fieldNames())
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func lookupField(name string) (trackField, bool) {
+	for _, f := range trackFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return trackField{}, false
+}
+
+func fieldNames() string {
+	names := make([]string, len(trackFields))
+	for i, f := range trackFields {
+		names[i] = f.name
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatPrice(p *beatport.Price) string {
+	if p == nil {
+		return ""
+	}
+	if p.Display != "" {
+		return p.Display
+	}
+	return fmt.Sprintf("%.2f %s", p.Value, p.Code)
+}
+
+// projectedTrack encodes only the selected fields of a track, as a JSON object
+// with the keys in the order they were selected.
+type projectedTrack struct {
+	fields []trackField
+	rank   int
+	track  beatport.Track
+}
+
+func (p projectedTrack) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range p.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value(p.rank, p.track))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// projectResult returns result with its tracks reduced to the selected
+// fields. result must encode its tracks under the "tracks" key.
+func projectResult(result any, tracks []beatport.Track, fields []trackField) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var projected map[string]any
+	if err := json.Unmarshal(data, &projected); err != nil {
+		return nil, err
+	}
+	rows := make([]projectedTrack, len(tracks))
+	for i, track := range tracks {
+		rows[i] = projectedTrack{fields: fields, rank: trackRank(i, track), track: track}
+	}
+	projected["tracks"] = rows
+	return projected, nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestParseFieldsUnknown(t *testing.T) {
+	_, err := parseFields("artist,tempo")
+	if err == nil {
+		t.Fatal("Expected an error for an unknown field")
+	}
+	if !strings.Contains(err.Error(), `"tempo"`) || !strings.Contains(err.Error(), "bpm") {
+		t.Errorf("Error should name the bad field and list the valid ones: %v", err)
+	}
+}
+
+func TestProjectResult(t *testing.T) {
+	fields, err := parseFields("rank, title ,BPM")
+	if err != nil {
+		t.Fatalf("parseFields failed: %v", err)
+	}
+	tracks := []beatport.Track{{Name: "First", BPM: 126}, {Name: "Second", BPM: 130}}
+	result := beatport.ChartResult{ChartType: beatport.ChartTop100, Tracks: tracks}
+
+	projected, err := projectResult(result, tracks, fields)
+	if err != nil {
+		t.Fatalf("projectResult failed: %v", err)
+	}
+	data, err := json.Marshal(projected)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"tracks":[{"rank":1,"title":"First","bpm":126},{"rank":2,"title":"Second","bpm":130}]`) {
+		t.Errorf("Unexpected projection: %s", data)
+	}
+	if !strings.Contains(string(data), `"chart_type":"top-100"`) {
+		t.Errorf("Projection dropped the chart metadata: %s", data)
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"beatport-top100/beatport"
+)
+
+// splitGenres splits a -genre value into the genres it lists, separated by
+// commas.
+func splitGenres(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// writeMultiText writes the chart of every genre as a section of its own,
+// headed by the genre's name, and its ID with withID.
+func writeMultiText(w io.Writer, chartName string, results []beatport.ChartResult, withID, color bool) {
+	for _, result := range results {
+		writeText(w, genreTitle(result.Genre, chartName, withID), result.Tracks, color)
+	}
+}
+
+// writeMultiCSV writes the charts of several genres as one CSV table, with
+// the genre's name and ID in the first columns of every row.
+func writeMultiCSV(w io.Writer, results []beatport.ChartResult, fields []trackField) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(csvHeader(append(genreFields(beatport.Genre{}), fields...)))
+	for _, result := range results {
+		writeCSVRows(cw, result.Tracks, append(genreFields(result.Genre), fields...))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeMultiJSON writes the charts of several genres as one JSON object,
+// keyed by genre slug. With fields, the tracks are reduced to those fields.
+func writeMultiJSON(w io.Writer, results []beatport.ChartResult, fields []trackField) error {
+	charts := make(map[string]any, len(results))
+	for _, result := range results {
+		key := result.Genre.Slug
+		if key == "" {
+			key = result.Genre.Name
+		}
+		var chart any = result
+		if fields != nil {
+			projected, err := projectResult(result, result.Tracks, fields)
+			if err != nil {
+				return err
+			}
+			chart = projected
+		}
+		charts[key] = chart
+	}
+	return writeJSON(w, charts)
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestSplitGenres(t *testing.T) {
+	got := splitGenres(" Techno, tech-house,,11 ")
+	if want := []string{"Techno", "tech-house", "11"}; !slices.Equal(got, want) {
+		t.Errorf("splitGenres = %q, want %q", got, want)
+	}
+}
+
+func TestWriteMultiJSON(t *testing.T) {
+	results := []beatport.ChartResult{
+		{Genre: beatport.Genre{ID: 6, Name: "Techno", Slug: "techno"}, Tracks: []beatport.Track{{ID: 1, Name: "One"}}},
+		{Genre: beatport.Genre{ID: 5, Name: "House", Slug: "house"}, Tracks: []beatport.Track{{ID: 2, Name: "Two"}}},
+	}
+	fields, err := parseFields("rank,title")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeMultiJSON(&buf, results, fields); err != nil {
+		t.Fatalf("writeMultiJSON failed: %v", err)
+	}
+	var charts map[string]struct {
+		Genre  beatport.Genre   `json:"genre"`
+		Tracks []map[string]any `json:"tracks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &charts); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(charts) != 2 || charts["techno"].Genre.ID != 6 || charts["house"].Tracks[0]["title"] != "Two" {
+		t.Errorf("Unexpected charts: %s", buf.String())
+	}
+
+	buf.Reset()
+	writeMultiText(&buf, "Top 100", results, false, false)
+	if !strings.Contains(buf.String(), "Techno Top 100 Tracks:") || !strings.Contains(buf.String(), "House Top 100 Tracks:") {
+		t.Errorf("Expected a section per genre, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteMultiWithGenreIDs(t *testing.T) {
+	results := []beatport.ChartResult{
+		{Genre: beatport.Genre{ID: 6, Name: "Techno", Slug: "techno"}, Tracks: []beatport.Track{{ID: 1, Name: "One"}}},
+		{Genre: beatport.Genre{ID: 5, Name: "House", Slug: "house"}, Tracks: []beatport.Track{{ID: 2, Name: "Two"}}},
+	}
+	fields, err := parseFields("rank,title")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeMultiCSV(&buf, results, fields); err != nil {
+		t.Fatalf("writeMultiCSV failed: %v", err)
+	}
+	want := "Genre,Genre ID,Rank,Title\nTechno,6,1,One\nHouse,5,1,Two\n"
+	if buf.String() != want {
+		t.Errorf("writeMultiCSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeMultiText(&buf, "Top 100", results, true, false)
+	if !strings.Contains(buf.String(), "Techno (ID: 6) Top 100 Tracks:") || !strings.Contains(buf.String(), "House (ID: 5) Top 100 Tracks:") {
+		t.Errorf("Expected the genre IDs in the headers, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"beatport-top100/beatport"
+)
+
+// artistResult is the JSON output of -artist.
+type artistResult struct {
+	Artist    beatport.Artist  `json:"artist"`
+	FetchedAt time.Time        `json:"fetched_at"`
+	Tracks    []beatport.Track `json:"tracks"`
+}
+
+// labelResult is the JSON output of -label.
+type labelResult struct {
+	Label     beatport.Label   `json:"label"`
+	FetchedAt time.Time        `json:"fetched_at"`
+	Tracks    []beatport.Track `json:"tracks"`
+}
+
+// searchResult is the JSON output of -search.
+type searchResult struct {
+	Query     string           `json:"query"`
+	FetchedAt time.Time        `json:"fetched_at"`
+	Tracks    []beatport.Track `json:"tracks"`
+}
+
+// djChartResult is the JSON output of -chart.
+type djChartResult struct {
+	ChartID   int              `json:"chart_id"`
+	FetchedAt time.Time        `json:"fetched_at"`
+	Tracks    []beatport.Track `json:"tracks"`
+}
+
+// releaseChartResult is the JSON output of -releases.
+type releaseChartResult struct {
+	Genre     beatport.Genre     `json:"genre"`
+	FetchedAt time.Time          `json:"fetched_at"`
+	Releases  []beatport.Release `json:"releases"`
+}
+
+// orEmpty returns s, or an empty slice if it is nil, so it is encoded as []
+// rather than null in JSON.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// trackRank returns the chart position of the track at index i of the
+// output: its Rank, or its position in the output if it has none.
+func trackRank(i int, track beatport.Track) int {
+	if track.Rank > 0 {
+		return track.Rank
+	}
+	return i + 1
+}
+
+func writeJSON(w io.Writer, result any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// writeCSV writes the tracks as CSV with a header row naming the fields.
+func writeCSV(w io.Writer, tracks []beatport.Track, fields []trackField) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(csvHeader(fields))
+	writeCSVRows(cw, tracks, fields)
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvHeader(fields []trackField) []string {
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.header
+	}
+	return header
+}
+
+func writeCSVRows(cw *csv.Writer, tracks []beatport.Track, fields []trackField) {
+	for i, track := range tracks {
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			row[j] = fmt.Sprint(f.value(trackRank(i, track), track))
+		}
+		_ = cw.Write(row)
+	}
+}
+
+// writeReleasesCSV writes a release chart as CSV.
+func writeReleasesCSV(w io.Writer, releases []beatport.Release) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Rank", "Artist", "Release", "Label", "Catalog Number", "Tracks", "Release Date"})
+	for i, r := range releases {
+		_ = cw.Write([]string{
+			strconv.Itoa(i + 1),
+			r.ArtistNames(),
+			r.Name,
+			r.LabelName(),
+			r.CatalogNumber,
+			strconv.Itoa(r.TrackCount),
+			r.ReleaseDate,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeReleasesText(w io.Writer, chartName string, releases []beatport.Release) {
+	fmt.Fprintf(w, "\n%s Releases:\n", chartName)
+	for i, r := range releases {
+		fmt.Fprintf(w, "%d. %s - %s [%s] (%d tracks)\n", i+1, r.ArtistNames(), r.Name, r.LabelName(), r.TrackCount)
+	}
+}
+
+// writeJSONLines writes every track as a JSON object on a line of its own,
+// with its rank set. With fields, only those fields are written.
+func writeJSONLines(w io.Writer, tracks []beatport.Track, fields []trackField) error {
+	enc := json.NewEncoder(w)
+	for i, track := range tracks {
+		rank := trackRank(i, track)
+		var line any
+		if fields != nil {
+			line = projectedTrack{fields: fields, rank: rank, track: track}
+		} else {
+			track.Rank = rank
+			line = track
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeGenres writes one line per genre with its name and ID.
+func writeGenres(w io.Writer, genres []beatport.Genre) {
+	for _, g := range genres {
+		fmt.Fprintf(w, "- %s (ID: %d)\n", g.Name, g.ID)
+	}
+}
+
+// writeM3U writes the tracks as an extended M3U playlist pointing at their
+// preview clips. Tracks without a preview are written as a comment so the
+// entries keep the chart order.
+func writeM3U(w io.Writer, tracks []beatport.Track) {
+	fmt.Fprintln(w, "#EXTM3U")
+	for i, track := range tracks {
+		title := fmt.Sprintf("%s - %s (%s)", track.ArtistNames(), track.Name, track.MixTitle())
+		if track.PreviewURL == "" {
+			fmt.Fprintf(w, "# %d. %s: no preview available\n", i+1, title)
+			continue
+		}
+		fmt.Fprintf(w, "#EXTINF:-1,%s\n%s\n", title, track.PreviewURL)
+	}
+}
+
+// writeTracklist writes the tracks as a numbered tracklist, one
+// "1. Artist - Title (Mix)" line per track, e.g. for the description of a
+// mix. With timestamps every line starts with a "[00:00]" placeholder to
+// fill in.
+func writeTracklist(w io.Writer, tracks []beatport.Track, timestamps bool) {
+	for i, track := range tracks {
+		if timestamps {
+			fmt.Fprint(w, "[00:00] ")
+		}
+		fmt.Fprintf(w, "%d. %s - %s", i+1, track.ArtistNames(), track.Name)
+		if mix := track.MixTitle(); mix != "" {
+			fmt.Fprintf(w, " (%s)", mix)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// ANSI escape sequences for the colored text output.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiMuted = "\x1b[3;90m" // italic gray
+)
+
+// genreTitle returns the title of a genre's chart, e.g. "Techno Top 100", or
+// "Techno (ID: 6) Top 100" with withID.
+func genreTitle(genre beatport.Genre, chartName string, withID bool) string {
+	if withID {
+		return fmt.Sprintf("%s (ID: %d) %s", genre.Name, genre.ID, chartName)
+	}
+	return genre.Name + " " + chartName
+}
+
+// writeText writes the tracks as an aligned table, optionally highlighting
+// the columns with ANSI colors. Without tracks it says so, rather than
+// writing a header with nothing under it.
+func writeText(w io.Writer, chartName string, tracks []beatport.Track, color bool) {
+	if len(tracks) == 0 {
+		fmt.Fprintf(w, "\nNo tracks found for %s.\n", chartName)
+		return
+	}
+
+	paint := func(style, s string) string {
+		if !color {
+			return s
+		}
+		return style + s + ansiReset
+	}
+
+	fmt.Fprintf(w, "\n%s Tracks:\n", chartName)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, track := range tracks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			paint(ansiDim, fmt.Sprintf("%d.", trackRank(i, track))),
+			paint(ansiBold, track.ArtistNames()),
+			track.Name,
+			paint(ansiMuted, "("+track.MixTitle()+")"))
+	}
+	tw.Flush()
+
+	var total time.Duration
+	for _, track := range tracks {
+		total += track.Duration()
+	}
+	if total > 0 {
+		fmt.Fprintf(w, "\nTotal length: %s\n", formatDuration(total))
+	}
+}
+
+// formatDuration formats d as h:mm:ss, or m:ss if it is shorter than an hour.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestWriteCSVEscapesFields(t *testing.T) {
+	tracks := []beatport.Track{
+		{
+			Name:    `Hello, "World"`,
+			MixName: "Original Mix",
+			Artists: []beatport.Artist{{Name: "Artist A"}, {Name: "Artist B"}},
+			BPM:     128,
+		},
+	}
+
+	fields, _ := parseFields(defaultCSVFields)
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, tracks, fields); err != nil {
+		t.Fatalf("writeCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v\n%s", err, buf.String())
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and one row, got %d records", len(records))
+	}
+
+	row := records[1]
+	if len(row) != len(records[0]) {
+		t.Errorf("Row has %d columns, header has %d", len(row), len(records[0]))
+	}
+	if row[1] != "Artist A, Artist B" || row[2] != `Hello, "World"` || row[5] != "128" {
+		t.Errorf("Unexpected row: %q", row)
+	}
+}
+
+func TestWriteTextAligned(t *testing.T) {
+	tracks := []beatport.Track{
+		{Name: "Alpha", MixName: "Original Mix", Artists: []beatport.Artist{{Name: "A"}}},
+		{Name: "Beta", MixName: "Dub Mix", Artists: []beatport.Artist{{Name: "A Much Longer Artist"}}},
+	}
+
+	var buf bytes.Buffer
+	writeText(&buf, "Top 100", tracks, false)
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("Uncolored output contains escape codes: %q", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %q", out)
+	}
+	if strings.Index(lines[1], "Alpha") != strings.Index(lines[2], "Beta") {
+		t.Errorf("Titles are not aligned:\n%s\n%s", lines[1], lines[2])
+	}
+
+	buf.Reset()
+	writeText(&buf, "Top 100", tracks, true)
+	if !strings.Contains(buf.String(), ansiBold+"A Much Longer Artist"+ansiReset) {
+		t.Errorf("Expected the artist in bold: %q", buf.String())
+	}
+}
+
+func TestWriteEmptyChart(t *testing.T) {
+	var buf bytes.Buffer
+	writeText(&buf, "Techno Top 100", nil, false)
+	if got := strings.TrimSpace(buf.String()); got != "No tracks found for Techno Top 100." {
+		t.Errorf("Unexpected text for an empty chart: %q", got)
+	}
+
+	buf.Reset()
+	if err := writeJSON(&buf, beatport.ChartResult{Tracks: orEmpty[beatport.Track](nil)}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"tracks": []`) {
+		t.Errorf("Expected an empty tracks array, got %s", buf.String())
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	tracks := []beatport.Track{
+		{ID: 1, Name: "Alpha"},
+		{ID: 2, Name: "Beta"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONLines(&buf, tracks, nil); err != nil {
+		t.Fatalf("writeJSONLines failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	for i, line := range lines {
+		var track beatport.Track
+		if err := json.Unmarshal([]byte(line), &track); err != nil {
+			t.Fatalf("Line %d is not a JSON object: %v\n%s", i+1, err, line)
+		}
+		if track.ID != tracks[i].ID || track.Rank != i+1 {
+			t.Errorf("Line %d: unexpected track %+v", i+1, track)
+		}
+	}
+
+	fields, _ := parseFields("rank,title")
+	buf.Reset()
+	if err := writeJSONLines(&buf, tracks, fields); err != nil {
+		t.Fatalf("writeJSONLines with fields failed: %v", err)
+	}
+	if want := "{\"rank\":1,\"title\":\"Alpha\"}\n{\"rank\":2,\"title\":\"Beta\"}\n"; buf.String() != want {
+		t.Errorf("Unexpected projected output:\n%s", buf.String())
+	}
+}
+
+func TestWriteTracklist(t *testing.T) {
+	tracks := []beatport.Track{
+		{Rank: 3, Name: "Alpha", Artists: []beatport.Artist{{Name: "One"}, {Name: "Two"}}, MixName: "Original Mix"},
+		{Rank: 1, Name: "Beta", Artists: []beatport.Artist{{Name: "Three"}}},
+	}
+
+	var buf bytes.Buffer
+	writeTracklist(&buf, tracks, false)
+	if want := "1. One, Two - Alpha (Original Mix)\n2. Three - Beta\n"; buf.String() != want {
+		t.Errorf("writeTracklist =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeTracklist(&buf, tracks[:1], true)
+	if want := "[00:00] 1. One, Two - Alpha (Original Mix)\n"; buf.String() != want {
+		t.Errorf("writeTracklist with timestamps = %q, want %q", buf.String(), want)
+	}
+}
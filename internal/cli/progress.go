@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressWidth is the width of the bar drawn by progressBar, in characters.
+const progressWidth = 30
+
+// progressBar draws the progress of a fetch on a single line, which it
+// clears again once the fetch is done.
+type progressBar struct {
+	w     io.Writer
+	drawn bool
+}
+
+// update draws the bar for done of total steps, or only the steps done if
+// the total is unknown. It is a beatport.ProgressFunc.
+func (b *progressBar) update(done, total int) {
+	if total > 0 && done >= total {
+		b.clear()
+		return
+	}
+	if total > 0 {
+		filled := progressWidth * done / total
+		fmt.Fprintf(b.w, "\r[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), done, total)
+	} else {
+		fmt.Fprintf(b.w, "\r%d fetched...", done)
+	}
+	b.drawn = true
+}
+
+// clear removes the bar, if it is drawn. It does nothing on a nil bar.
+func (b *progressBar) clear() {
+	if b != nil && b.drawn {
+		fmt.Fprint(b.w, "\r\033[K")
+		b.drawn = false
+	}
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	bar := &progressBar{w: &buf}
+
+	bar.update(0, 4)
+	bar.update(2, 4)
+	if !strings.HasSuffix(buf.String(), "\r[###############---------------] 2/4") {
+		t.Errorf("Unexpected bar: %q", buf.String())
+	}
+
+	bar.update(4, 4)
+	if !strings.HasSuffix(buf.String(), "\r\033[K") {
+		t.Errorf("Expected the bar to be cleared when done, got %q", buf.String())
+	}
+
+	buf.Reset()
+	bar.update(3, 0)
+	if buf.String() != "\r3 fetched..." {
+		t.Errorf("Unexpected progress without a total: %q", buf.String())
+	}
+	bar.clear()
+	bar.clear()
+	if strings.Count(buf.String(), "\033[K") != 1 {
+		t.Errorf("Expected the bar to be cleared once, got %q", buf.String())
+	}
+}
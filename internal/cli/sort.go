@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"beatport-top100/beatport"
+)
+
+// trackSorts are the orders -sort accepts.
+var trackSorts = map[string]func(a, b beatport.Track) int{
+	"rank": func(a, b beatport.Track) int { return cmp.Compare(a.Rank, b.Rank) },
+	"bpm":  func(a, b beatport.Track) int { return cmp.Compare(a.BPM, b.BPM) },
+	"key":  func(a, b beatport.Track) int { return beatport.CompareKeys(a.Key, b.Key) },
+	"artist": func(a, b beatport.Track) int {
+		return strings.Compare(strings.ToLower(a.ArtistNames()), strings.ToLower(b.ArtistNames()))
+	},
+	"title": func(a, b beatport.Track) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	},
+}
+
+const trackSortNames = "rank, bpm, key, artist, title"
+
+// validateSort checks that field is an order -sort accepts.
+func validateSort(field string) error {
+	if _, ok := trackSorts[field]; !ok {
+		return fmt.Errorf("unknown sort field %q, valid fields are: %s", field, trackSortNames)
+	}
+	return nil
+}
+
+// sortTracks sorts the tracks in place by field, keeping tracks that compare
+// equal in chart order. Tracks keep their rank, and tracks without one are
+// ranked by their position before sorting.
+func sortTracks(tracks []beatport.Track, field string, desc bool) {
+	for i := range tracks {
+		tracks[i].Rank = trackRank(i, tracks[i])
+	}
+	compare := trackSorts[field]
+	slices.SortStableFunc(tracks, func(a, b beatport.Track) int {
+		if desc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
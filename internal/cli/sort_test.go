@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestSortTracks(t *testing.T) {
+	newTracks := func() []beatport.Track {
+		return []beatport.Track{
+			{ID: 1, BPM: 128, Key: &beatport.Key{CamelotNumber: 8, CamelotLetter: "A"}},
+			{ID: 2, BPM: 124, Key: &beatport.Key{CamelotNumber: 12, CamelotLetter: "B"}},
+			{ID: 3, BPM: 128, Key: &beatport.Key{CamelotNumber: 8, CamelotLetter: "B"}},
+			{ID: 4, BPM: 122},
+		}
+	}
+
+	tests := []struct {
+		field string
+		desc  bool
+		want  []int
+	}{
+		{"bpm", false, []int{4, 2, 1, 3}},
+		{"bpm", true, []int{1, 3, 2, 4}},
+		{"key", false, []int{1, 3, 2, 4}},
+		{"rank", true, []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		tracks := newTracks()
+		sortTracks(tracks, tt.field, tt.desc)
+		for i, id := range tt.want {
+			if tracks[i].ID != id {
+				t.Errorf("-sort %s (desc %v): expected IDs %v, got track %d at %d", tt.field, tt.desc, tt.want, tracks[i].ID, i)
+				break
+			}
+		}
+	}
+
+	tracks := newTracks()
+	sortTracks(tracks, "bpm", false)
+	if tracks[0].Rank != 4 {
+		t.Errorf("Expected tracks to keep their chart rank, got %+v", tracks[0])
+	}
+	if err := validateSort("energy"); err == nil {
+		t.Error("Expected an error for an unknown sort field")
+	}
+}
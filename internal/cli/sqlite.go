@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"database/sql"
+	"time"
+
+	"beatport-top100/beatport"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables of the -sqlite chart history. Genres,
+// artists and tracks are upserted, chart entries are appended for every
+// fetch.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS genres (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	slug TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS artists (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	slug TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tracks (
+	id             INTEGER PRIMARY KEY,
+	name           TEXT NOT NULL,
+	mix_name       TEXT NOT NULL,
+	bpm            INTEGER NOT NULL,
+	key            TEXT NOT NULL,
+	camelot        TEXT NOT NULL,
+	release_date   TEXT NOT NULL,
+	label          TEXT NOT NULL,
+	catalog_number TEXT NOT NULL,
+	length_ms      INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS track_artists (
+	track_id  INTEGER NOT NULL REFERENCES tracks (id),
+	artist_id INTEGER NOT NULL REFERENCES artists (id),
+	position  INTEGER NOT NULL,
+	PRIMARY KEY (track_id, artist_id)
+);
+CREATE TABLE IF NOT EXISTS chart_entries (
+	genre_id   INTEGER NOT NULL REFERENCES genres (id),
+	chart_type TEXT NOT NULL,
+	track_id   INTEGER NOT NULL REFERENCES tracks (id),
+	rank       INTEGER NOT NULL,
+	fetched_at TEXT NOT NULL,
+	PRIMARY KEY (genre_id, chart_type, fetched_at, rank)
+);
+CREATE INDEX IF NOT EXISTS chart_entries_track ON chart_entries (track_id);
+`
+
+// writeSQLite records a fetched chart in the SQLite database at path,
+// creating it if needed.
+func writeSQLite(path string, result beatport.ChartResult) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	g := result.Genre
+	if _, err := tx.Exec(`INSERT INTO genres (id, name, slug) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, slug = excluded.slug`,
+		g.ID, g.Name, g.Slug); err != nil {
+		return err
+	}
+
+	fetchedAt := result.FetchedAt.UTC().Format(time.RFC3339)
+	for i, t := range result.Tracks {
+		if _, err := tx.Exec(`INSERT INTO tracks (id, name, mix_name, bpm, key, camelot, release_date, label, catalog_number, length_ms)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, mix_name = excluded.mix_name, bpm = excluded.bpm,
+				key = excluded.key, camelot = excluded.camelot, release_date = excluded.release_date,
+				label = excluded.label, catalog_number = excluded.catalog_number, length_ms = excluded.length_ms`,
+			t.ID, t.Name, t.MixName, t.BPM, t.KeyName(), t.Key.Camelot(), t.ReleaseDate, t.LabelName(), t.CatalogNumber, t.LengthMs); err != nil {
+			return err
+		}
+		for pos, a := range t.Artists {
+			if _, err := tx.Exec(`INSERT INTO artists (id, name, slug) VALUES (?, ?, ?)
+				ON CONFLICT (id) DO UPDATE SET name = excluded.name, slug = excluded.slug`,
+				a.ID, a.Name, a.Slug); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`INSERT INTO track_artists (track_id, artist_id, position) VALUES (?, ?, ?)
+				ON CONFLICT (track_id, artist_id) DO UPDATE SET position = excluded.position`,
+				t.ID, a.ID, pos+1); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO chart_entries (genre_id, chart_type, track_id, rank, fetched_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			g.ID, result.ChartType, t.ID, trackRank(i, t), fetchedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beatport-top100/beatport"
+)
+
+func TestWriteSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "charts.db")
+	artist := beatport.Artist{ID: 10, Name: "Example Artist", Slug: "example-artist"}
+	result := beatport.ChartResult{
+		Genre:     beatport.Genre{ID: 5, Name: "House", Slug: "house"},
+		ChartType: beatport.ChartTop100,
+		FetchedAt: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+		Tracks: []beatport.Track{
+			{Rank: 1, ID: 1, Name: "Night Drive", Artists: []beatport.Artist{artist}, BPM: 128},
+			{Rank: 2, ID: 2, Name: "Pulse", Artists: []beatport.Artist{artist}, BPM: 126},
+		},
+	}
+	if err := writeSQLite(path, result); err != nil {
+		t.Fatalf("writeSQLite failed: %v", err)
+	}
+
+	// A week later the tracks swapped places
+	result.FetchedAt = result.FetchedAt.AddDate(0, 0, 7)
+	result.Tracks[0].ID, result.Tracks[1].ID = 2, 1
+	result.Tracks[0].Name, result.Tracks[1].Name = "Pulse", "Night Drive"
+	if err := writeSQLite(path, result); err != nil {
+		t.Fatalf("Second writeSQLite failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	counts := map[string]int{"genres": 1, "artists": 1, "tracks": 2, "chart_entries": 4}
+	for table, want := range counts {
+		var got int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&got); err != nil {
+			t.Fatalf("Counting %s failed: %v", table, err)
+		}
+		if got != want {
+			t.Errorf("Expected %d rows in %s, got %d", want, table, got)
+		}
+	}
+
+	var rank int
+	err = db.QueryRow(`SELECT rank FROM chart_entries WHERE track_id = 1 ORDER BY fetched_at DESC LIMIT 1`).Scan(&rank)
+	if err != nil || rank != 2 {
+		t.Errorf("Expected track 1 to be at rank 2 in the latest chart, got %d (%v)", rank, err)
+	}
+}
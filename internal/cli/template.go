@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"beatport-top100/beatport"
+)
+
+// templateFuncs are the extra functions available in -template files.
+var templateFuncs = template.FuncMap{
+	"join": func(artists []beatport.Artist, sep string) string {
+		names := make([]string, len(artists))
+		for i, a := range artists {
+			names[i] = a.Name
+		}
+		return strings.Join(names, sep)
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseTemplate parses the -template file at path.
+func parseTemplate(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+}
+
+// writeTemplate executes tmpl once per track, with the track as data. If the
+// template defines "header" or "footer" templates, they are executed once
+// before and after the tracks, with all the tracks as data.
+func writeTemplate(w io.Writer, tmpl *template.Template, tracks []beatport.Track) error {
+	if header := tmpl.Lookup("header"); header != nil {
+		if err := header.Execute(w, tracks); err != nil {
+			return err
+		}
+	}
+	for i, track := range tracks {
+		track.Rank = trackRank(i, track)
+		if err := tmpl.Execute(w, track); err != nil {
+			return err
+		}
+	}
+	if footer := tmpl.Lookup("footer"); footer != nil {
+		return footer.Execute(w, tracks)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"beatport-top100/beatport"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.tmpl")
+	tmplText := `{{define "header"}}| # | Artist | Title |
+|---|---|---|
+{{end}}| {{.Rank}} | {{join .Artists " & "}} | {{upper .Name}} |
+`
+	if err := os.WriteFile(path, []byte(tmplText), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := parseTemplate(path)
+	if err != nil {
+		t.Fatalf("parseTemplate failed: %v", err)
+	}
+	tracks := []beatport.Track{
+		{Name: "Night Drive", Artists: []beatport.Artist{{Name: "A"}, {Name: "B"}}},
+		{Rank: 7, Name: "Pulse", Artists: []beatport.Artist{{Name: "C"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTemplate(&buf, tmpl, tracks); err != nil {
+		t.Fatalf("writeTemplate failed: %v", err)
+	}
+	want := "| # | Artist | Title |\n|---|---|---|\n| 1 | A & B | NIGHT DRIVE |\n| 7 | C | PULSE |\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"beatport-top100/beatport"
+)
+
+// Set at build time with
+// -ldflags "-X beatport-top100/internal/cli.commit=... -X beatport-top100/internal/cli.date=...",
+// and the version with -X beatport-top100/beatport.version=.... Anything
+// left empty is taken from the build info Go embeds in the binary.
+var (
+	commit string
+	date   string
+)
+
+// versionString describes the build for -version.
+func versionString() string {
+	c, d := commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && c == "":
+				c = s.Value
+			case s.Key == "vcs.time" && d == "":
+				d = s.Value
+			}
+		}
+	}
+	if c == "" {
+		c = "unknown"
+	}
+	if d == "" {
+		d = "unknown"
+	}
+	return fmt.Sprintf("%s %s (commit %s, built %s)", beatport.ClientName, beatport.Version(), c, d)
+}
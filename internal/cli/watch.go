@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"beatport-top100/beatport"
+)
+
+// chartWatch is a chart that -watch fetches over and over.
+type chartWatch struct {
+	client    *beatport.Client
+	genre     beatport.Genre
+	chartType string
+	chartName string
+	limit     int
+
+	// genreID includes the genre's ID in the chart's title.
+	genreID bool
+
+	// login authenticates again once the token can no longer be refreshed.
+	login func(ctx context.Context) error
+}
+
+// run fetches the chart every interval until ctx is cancelled. The first
+// fetch is written in full, unless there is a previous chart to compare it
+// with; after that only the changes are written. Failed fetches are reported
+// and retried on the next cycle.
+func (cw chartWatch) run(ctx context.Context, w io.Writer, prev *beatport.ChartResult, interval time.Duration, jsonOutput, color bool) error {
+	for {
+		current, err := cw.fetch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", cw.chartName, err)
+		} else {
+			if err := cw.write(w, prev, current, jsonOutput, color); err != nil {
+				return err
+			}
+			prev = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// write writes the chart in full if there is no previous one, or else what
+// changed since the previous one, if anything.
+func (cw chartWatch) write(w io.Writer, prev, current *beatport.ChartResult, jsonOutput, color bool) error {
+	if prev == nil {
+		if jsonOutput {
+			return writeJSON(w, current)
+		}
+		writeText(w, genreTitle(cw.genre, cw.chartName, cw.genreID), current.Tracks, color)
+		return nil
+	}
+
+	diff := beatport.DiffCharts(prev.Tracks, current.Tracks)
+	if !diff.Changed() {
+		return nil
+	}
+	if jsonOutput {
+		return writeJSON(w, diff)
+	}
+	writeDiff(w, cw.chartName, prev, diff, current.Tracks)
+	return nil
+}
+
+// fetch fetches the chart once, renewing the token first if it expired
+// while waiting.
+func (cw chartWatch) fetch(ctx context.Context) (*beatport.ChartResult, error) {
+	if !cw.client.TokenValid() {
+		if err := cw.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+	fetchedAt := time.Now().UTC()
+	tracks, err := cw.client.GetGenreChartCtx(ctx, cw.genre.ID, cw.chartType, cw.limit)
+	if err != nil {
+		return nil, err
+	}
+	return &beatport.ChartResult{
+		Genre:     cw.genre,
+		ChartType: cw.chartType,
+		FetchedAt: fetchedAt,
+		Tracks:    orEmpty(tracks),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"beatport-top100/beatport"
+)
+
+func TestChartWatchWrite(t *testing.T) {
+	cw := chartWatch{chartName: "Top 100"}
+	first := &beatport.ChartResult{
+		FetchedAt: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+		Tracks:    []beatport.Track{{Rank: 1, ID: 1, Name: "Alpha"}, {Rank: 2, ID: 2, Name: "Beta"}},
+	}
+
+	var buf bytes.Buffer
+	if err := cw.write(&buf, nil, first, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Alpha") || !strings.Contains(buf.String(), "Beta") {
+		t.Errorf("Expected the full chart on the first fetch, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := cw.write(&buf, first, first, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for an unchanged chart, got:\n%s", buf.String())
+	}
+
+	swapped := &beatport.ChartResult{
+		Tracks: []beatport.Track{{Rank: 1, ID: 2, Name: "Beta"}, {Rank: 2, ID: 1, Name: "Alpha"}},
+	}
+	buf.Reset()
+	if err := cw.write(&buf, first, swapped, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "▲1") || !strings.Contains(buf.String(), "▼1") {
+		t.Errorf("Expected the moves to be marked, got:\n%s", buf.String())
+	}
+}
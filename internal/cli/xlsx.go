@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"beatport-top100/beatport"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultXLSXFields are the spreadsheet columns written when -fields is not
+// given.
+const defaultXLSXFields = "rank,artist,title,mix,bpm,key,label"
+
+// xlsxSheet is the name of the worksheet the tracks are written to.
+const xlsxSheet = "Chart"
+
+// maxXLSXColumnWidth caps the auto-sized column width, so a long artist list
+// doesn't push the other columns off screen.
+const maxXLSXColumnWidth = 60
+
+// writeXLSX writes the tracks as an Excel workbook at path, with a bold
+// header row and every column sized to fit its contents.
+func writeXLSX(path string, tracks []beatport.Track, fields []trackField) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return err
+	}
+
+	widths := make([]int, len(fields))
+	setCell := func(col, row int, value any) error {
+		cell, err := excelize.CoordinatesToCellName(col+1, row+1)
+		if err != nil {
+			return err
+		}
+		widths[col] = max(widths[col], utf8.RuneCountInString(fmt.Sprint(value)))
+		return f.SetCellValue(xlsxSheet, cell, value)
+	}
+
+	for col, field := range fields {
+		if err := setCell(col, 0, field.header); err != nil {
+			return err
+		}
+	}
+	for i, track := range tracks {
+		for col, field := range fields {
+			if err := setCell(col, i+1, field.value(trackRank(i, track), track)); err != nil {
+				return err
+			}
+		}
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	if err := f.SetRowStyle(xlsxSheet, 1, 1, bold); err != nil {
+		return err
+	}
+	for col, width := range widths {
+		name, err := excelize.ColumnNumberToName(col + 1)
+		if err != nil {
+			return err
+		}
+		// A little padding, as Excel's width unit is roughly one character
+		if err := f.SetColWidth(xlsxSheet, name, name, float64(min(width+2, maxXLSXColumnWidth))); err != nil {
+			return err
+		}
+	}
+
+	// Unlike SaveAs, os.Create doesn't mark the workbook executable
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteTo(out); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
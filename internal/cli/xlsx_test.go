@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"beatport-top100/beatport"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.xlsx")
+	tracks := []beatport.Track{
+		{Rank: 1, Name: "Night Drive", MixName: "Original Mix", BPM: 128,
+			Artists: []beatport.Artist{{Name: "Example Artist"}},
+			Key:     &beatport.Key{Name: "A Minor"},
+			Label:   &beatport.Label{Name: "Example Records"}},
+		{Rank: 2, Name: "Pulse", MixName: "Extended Mix", BPM: 126,
+			Artists: []beatport.Artist{{Name: "Fixture Collective"}}},
+	}
+	fields, _ := parseFields(defaultXLSXFields)
+	if err := writeXLSX(path, tracks, fields); err != nil {
+		t.Fatalf("writeXLSX failed: %v", err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(xlsxSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"Rank", "Artist", "Title", "Mix Name", "BPM", "Key", "Label"},
+		{"1", "Example Artist", "Night Drive", "Original Mix", "128", "A Minor", "Example Records"},
+		{"2", "Fixture Collective", "Pulse", "Extended Mix", "126"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %d rows, got %d: %q", len(want), len(rows), rows)
+	}
+	for i := range want {
+		if len(rows[i]) != len(want[i]) {
+			t.Errorf("Row %d: expected %q, got %q", i, want[i], rows[i])
+			continue
+		}
+		for j := range want[i] {
+			if rows[i][j] != want[i][j] {
+				t.Errorf("Row %d: expected %q, got %q", i, want[i], rows[i])
+				break
+			}
+		}
+	}
+
+	// The artist column is sized to its longest value
+	width, err := f.GetColWidth(xlsxSheet, "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width != float64(len("Fixture Collective")+2) {
+		t.Errorf("Expected column B width %d, got %v", len("Fixture Collective")+2, width)
+	}
+}